@@ -0,0 +1,49 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Applies the delta to the first sequence: removed elements are dropped,
+// added elements are taken from the second sequence at their marks and the
+// rest of the first sequence is kept. The result equals the second sequence
+// exactly when the delta is a correct transform between the two.
+// Returns an error if the marks are out of bounds or do not keep
+// the same number of elements from both sequences.
+func Apply[T any](a, b []T, d Delta) ([]T, error) {
+	if err := checkMarks(d.Removed, len(a), 0); err != nil {
+		return nil, err
+	}
+	if err := checkMarks(d.Added, len(b), 1); err != nil {
+		return nil, err
+	}
+	if len(a)-count(d.Removed) != len(b)-count(d.Added) {
+		return nil, &LengthError{len(a) - count(d.Removed), len(b) - count(d.Added)}
+	}
+
+	var result []T = make([]T, 0, len(b))
+	var x int
+	for _, c := range d.changes() {
+		result = append(result, a[x:c.x]...)
+		result = append(result, b[c.y:c.lenY]...)
+		x = c.lenX
+	}
+	return append(result, a[x:]...), nil
+}
+
+// Reports whether both deltas transform the first sequence into the second
+// one. This tells whether deltas are correct independently of whether
+// they are minimal, e.g. when comparing custom strategies to Diff.
+func EquivalentTransforms[T comparable](a, b []T, d1, d2 Delta) bool {
+	return transforms(a, b, d1) && transforms(a, b, d2)
+}
+
+// Reports whether the delta transforms the first sequence into the second one
+func transforms[T comparable](a, b []T, d Delta) bool {
+	var result, err = Apply(a, b, d)
+	if err != nil || len(result) != len(b) {
+		return false
+	}
+	for i := range result {
+		if result[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
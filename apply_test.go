@@ -0,0 +1,41 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	var a, b []byte = []byte("abcdefgh"), []byte("abbcedfh")
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))
+
+	result, err := Apply(a, b, delta)
+	if err != nil || string(result) != string(b) {
+		t.Errorf("Unexpected result %q %v", result, err)
+	}
+	if _, err := Apply(a, b, Delta{Added: []Mark{Mark{2, 3}}}); err == nil {
+		t.Errorf("Expected an error for an unbalanced delta")
+	}
+	if _, err := Apply(a, b, Delta{Added: []Mark{Mark{8, 9}}, Removed: []Mark{Mark{0, 1}}}); err == nil {
+		t.Errorf("Expected an error for an out of bounds mark")
+	}
+}
+
+func TestEquivalentTransforms(t *testing.T) {
+	var a, b []byte = []byte("abcab"), []byte("ab")
+	var first Delta = Delta{Removed: []Mark{Mark{2, 5}}}
+	var last Delta = Delta{Removed: []Mark{Mark{0, 3}}}
+	var all Delta = Delta{Removed: []Mark{Mark{0, 5}}, Added: []Mark{Mark{0, 2}}}
+	var wrong Delta = Delta{Removed: []Mark{Mark{0, 1}, Mark{2, 4}}}
+
+	if !EquivalentTransforms(a, b, first, last) {
+		t.Errorf("Expected deltas keeping either occurrence to be equivalent")
+	}
+	if !EquivalentTransforms(a, b, first, all) {
+		t.Errorf("Expected a non-minimal delta to be equivalent")
+	}
+	if EquivalentTransforms(a, b, first, wrong) {
+		t.Errorf("Expected an incorrect delta not to be equivalent")
+	}
+}
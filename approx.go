@@ -0,0 +1,78 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Diffs the provided data approximately by comparing only a sample of the
+// match matrix. Every 1/sample-th element of the first sequence is compared
+// against the whole second sequence, each match found is extended along
+// its diagonal and the recursion then splits on the longest of these runs.
+// A sample in (0, 1] thus sets the density of the comparisons: any common
+// run at least 1/sample elements long is found, while shorter ones may be
+// missed and reported as changes. The result is always a valid delta,
+// but not necessarily the one Diff would return. It costs about
+// n*m*sample comparisons instead of n*m, plus about one for each element
+// of the runs found, as probes within a run found earlier are skipped.
+// Highly repetitive input, with many long runs, thus costs more.
+func DiffApprox(data Interface, sample float64) Delta {
+	var len1, len2 = data.Len()
+	var stride int = 1
+	if sample > 0 && sample < 1 {
+		stride = int(1/sample + 0.5)
+	}
+
+	var runs []match
+	// The end in the first sequence of the last run found on each diagonal
+	var covered map[int]int = make(map[int]int)
+	for i := 0; i < len1; i += stride {
+		for j := 0; j < len2; j++ {
+			if end, found := covered[i-j]; found && i < end {
+				continue // Within a run that is already extended
+			}
+			if !data.Equal(i, j) {
+				continue
+			}
+			// Extend the probe to the whole run along the diagonal
+			var start point = point{i, j}
+			for start.x > 0 && start.y > 0 && data.Equal(start.x-1, start.y-1) {
+				start = point{start.x - 1, start.y - 1}
+			}
+			var length int = i - start.x + 1
+			for start.x+length < len1 && start.y+length < len2 && data.Equal(start.x+length, start.y+length) {
+				length++
+			}
+			covered[i-j] = start.x + length
+			runs = append(runs, match{start, length})
+		}
+	}
+	return approxDiff(runs, box{point{0, 0}, len1, len2})
+}
+
+// Splits the bounds on the longest of the runs that overlap with them
+func approxDiff(runs []match, bounds box) Delta {
+	var best match
+	for _, r := range runs {
+		if m := clip(r, bounds); m.length > best.length {
+			best = m
+		}
+	}
+
+	if best.length == 0 {
+		return unmatched(bounds)
+	}
+
+	var left Delta = approxDiff(runs, box{bounds.point, best.x, best.y})
+	var right Delta = approxDiff(runs, box{point{best.x + best.length, best.y + best.length}, bounds.lenX, bounds.lenY})
+
+	var result Delta
+	result.Added = append(left.Added, right.Added...)
+	result.Removed = append(left.Removed, right.Removed...)
+	return result
+}
+
+// Returns the part of the run that lies within the bounds
+func clip(r match, bounds box) match {
+	var from int = max(0, max(bounds.x-r.x, bounds.y-r.y))
+	var to int = min(r.length, min(bounds.lenX-r.x, bounds.lenY-r.y))
+	if to <= from {
+		return match{}
+	}
+	return match{point{r.x + from, r.y + from}, to - from}
+}
@@ -0,0 +1,68 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDiffApprox(t *testing.T) {
+	// Two large sequences that share a few long blocks, moved around
+	var random *rand.Rand = rand.New(rand.NewSource(1))
+	var blocks [][]int
+	for i := 0; i < 6; i++ {
+		var block []int
+		for j := 0; j < 100; j++ {
+			block = append(block, random.Intn(1000))
+		}
+		blocks = append(blocks, block)
+	}
+	var a, b []int
+	for _, i := range []int{0, 1, 2, 3, 4, 5} {
+		a = append(a, blocks[i]...)
+	}
+	for _, i := range []int{0, 2, 1, 3, 5} {
+		b = append(b, blocks[i]...)
+	}
+
+	var calls int
+	var data Interface = WithEqual(len(a), len(b), func(i, j int) bool {
+		calls++
+		return a[i] == b[j]
+	})
+
+	var exact Delta = Diff(data)
+	var exactCalls int = calls
+	calls = 0
+	var approx Delta = DiffApprox(data, 0.05)
+	var approxCalls int = calls
+
+	if err := Verify(data, approx, nil); err != nil {
+		t.Fatalf("Unexpected invalid approximate delta: %v", err)
+	}
+	if approxCalls*10 > exactCalls {
+		t.Errorf("Expected far fewer comparisons, got %d against %d", approxCalls, exactCalls)
+	}
+	var exactRemoved, _ = exact.Len()
+	var approxRemoved, _ = approx.Len()
+	if approxRemoved > exactRemoved+20 {
+		t.Errorf("Expected the major common blocks to be found, got %d removed against %d", approxRemoved, exactRemoved)
+	}
+}
+
+func TestDiffApproxRuns(t *testing.T) {
+	// Every probe on the main diagonal hits the same run, which is extended once
+	var length, stride int = 1000, 20
+	var calls int
+	var data Interface = WithEqual(length, length, func(i, j int) bool {
+		calls++
+		return i == j
+	})
+
+	var delta Delta = DiffApprox(data, 1/float64(stride))
+	if removed, added := delta.Len(); removed != 0 || added != 0 {
+		t.Errorf("Unexpected delta for equal sequences %v", delta)
+	}
+	if limit := length*length/stride + 2*length; calls > limit {
+		t.Errorf("Unexpected number of comparisons\nGot %d\nExpected at most %d", calls, limit)
+	}
+}
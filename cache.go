@@ -0,0 +1,97 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"container/list"
+)
+
+// An equalityCache compares elements on demand and keeps the results
+// of a bounded number of comparisons, evicting the least recently used
+type equalityCache struct {
+	data    Interface
+	size    int
+	entries map[point]*list.Element
+	// Most recently used entries first
+	order *list.List
+}
+
+type cacheEntry struct {
+	point
+	equal bool
+}
+
+func newEqualityCache(data Interface, size int) *equalityCache {
+	return &equalityCache{data: data, size: size, entries: make(map[point]*list.Element), order: list.New()}
+}
+
+// Reports whether the elements at the point are equal, comparing them
+// unless the result is cached
+func (c *equalityCache) equal(p point) bool {
+	if e, found := c.entries[p]; found {
+		c.order.MoveToFront(e)
+		return e.Value.(*cacheEntry).equal
+	}
+
+	var result bool = c.data.Equal(p.x, p.y)
+	if c.order.Len() < c.size {
+		c.entries[p] = c.order.PushFront(&cacheEntry{p, result})
+		return result
+	}
+	// Reuse the least recently used entry, so that a full cache does not allocate
+	var oldest *list.Element = c.order.Back()
+	var entry *cacheEntry = oldest.Value.(*cacheEntry)
+	delete(c.entries, entry.point)
+	entry.point, entry.equal = p, result
+	c.entries[p] = oldest
+	c.order.MoveToFront(oldest)
+	return result
+}
+
+// A MatrixCache diffs a fixed first sequence against successive versions
+// of a second one, e.g. as it is being edited, reusing the match matrix
+// of the previous version. Only the columns of the elements that changed
+// between the versions are compared anew, the rest are copied over.
+type MatrixCache[T any] struct {
+	a, b  []T
+	equal func(T, T) bool
+	mx    *matrix
+}
+
+// Returns a MatrixCache for diffing against the first sequence, which must
+// not be modified while the result is in use, comparing elements with equal
+func NewMatrixCache[T any](a []T, equal func(T, T) bool) *MatrixCache[T] {
+	return &MatrixCache[T]{a: a, equal: equal}
+}
+
+// Diffs the first sequence against the next version of the second one and
+// returns the same delta as Config.Diff. The version is compared with the
+// previous one to find their common prefix and suffix, which takes up to
+// len(b) comparisons. The columns of the matrix for these are reused and
+// only those in between are filled by comparing them with the first
+// sequence, so a small edit takes about len(a) times the edit's length
+// comparisons instead of len(a) * len(b). The first call fills the whole matrix.
+func (c *MatrixCache[T]) Diff(b []T) Delta {
+	var mx *matrix = Config{}.matrix(len(c.a), len(b))
+	var prefix, suffix int
+	if c.mx != nil {
+		prefix, suffix = CommonAffixes(WithEqual(len(c.b), len(b), func(i, j int) bool {
+			return c.equal(c.b[i], b[j])
+		}))
+	}
+
+	for i := range c.a {
+		for j := 0; j < len(b); j++ {
+			var value bool
+			switch {
+			case j < prefix:
+				value = c.mx.v.Peek(c.mx.at(point{i, j}))
+			case j >= len(b)-suffix:
+				value = c.mx.v.Peek(c.mx.at(point{i, j - len(b) + len(c.b)}))
+			default:
+				value = c.equal(c.a[i], b[j])
+			}
+			mx.v.Poke(mx.at(point{i, j}), value)
+		}
+	}
+	c.b, c.mx = b, mx
+	return mx.recursiveDiff(box{point{0, 0}, len(c.a), len(b)}, 1)
+}
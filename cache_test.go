@@ -0,0 +1,136 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestCacheSize(t *testing.T) {
+	var random *rand.Rand = rand.New(rand.NewSource(1))
+	var generate = func(length int) string {
+		var builder strings.Builder
+		for i := 0; i < length; i++ {
+			builder.WriteByte("abc"[random.Intn(3)])
+		}
+		return builder.String()
+	}
+
+	for n := 0; n < 200; n++ {
+		var seq1, seq2 string = generate(random.Intn(20)), generate(random.Intn(20))
+		var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+			return seq1[i] == seq2[j]
+		})
+		var expected Delta = Config{}.Diff(data)
+		for _, size := range []int{1, 7, 1000} {
+			if delta := (Config{CacheSize: size}).Diff(data); fmt.Sprint(delta) != fmt.Sprint(expected) {
+				t.Fatalf("Unexpected delta with a cache of %d for [%s] [%s]\nGot %v\nExpected %v", size, seq1, seq2, delta, expected)
+			}
+		}
+	}
+}
+
+func TestEqualityCache(t *testing.T) {
+	var calls int
+	var cache *equalityCache = newEqualityCache(WithEqual(3, 3, func(i, j int) bool {
+		calls++
+		return i == j
+	}), 2)
+
+	for _, p := range []point{{0, 0}, {1, 2}, {0, 0}, {2, 2}, {1, 2}} {
+		if cache.equal(p) != (p.x == p.y) {
+			t.Errorf("Unexpected equality for %v", p)
+		}
+	}
+	// The second lookup of (0, 0) is cached while (1, 2) was evicted by then
+	if calls != 4 {
+		t.Errorf("Unexpected number of comparisons\nGot %d\nExpected 4", calls)
+	}
+	if cache.order.Len() != 2 || len(cache.entries) != 2 {
+		t.Errorf("Expected the cache to hold 2 entries, got %d", cache.order.Len())
+	}
+}
+
+// The allocations with a cache stay the same as the input grows,
+// while those of the match matrix grow with the product of the lengths
+func BenchmarkCacheSize(b *testing.B) {
+	var seq1, seq2 string = strings.Repeat("abcdefghij", 40), strings.Repeat("abcdXfghij", 40)
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+	for _, size := range []int{0, 1024} {
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				Config{CacheSize: size}.Diff(data)
+			}
+		})
+	}
+}
+
+func TestMatrixCache(t *testing.T) {
+	var a []byte = []byte("the quick brown fox jumps over the lazy dog")
+	var calls int
+	var equal = func(x, y byte) bool {
+		calls++
+		return x == y
+	}
+	var cache *MatrixCache[byte] = NewMatrixCache(a, equal)
+
+	var versions []string = []string{
+		"the quick brown fox jumps over the lazy dog",
+		"the quick brown cat jumps over the lazy dog",
+		"the quick brown cat jumps over a lazy dog",
+		"a quick brown cat jumps over a lazy dog!",
+		"",
+		"the dog",
+	}
+	for _, version := range versions {
+		var b []byte = []byte(version)
+		calls = 0
+		var delta Delta = cache.Diff(b)
+		var expected Delta = Config{}.Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+			return a[i] == b[j]
+		}))
+		if fmt.Sprint(delta) != fmt.Sprint(expected) {
+			t.Errorf("Unexpected delta for [%s]\nGot %v\nExpected %v", version, delta, expected)
+		}
+		if version == versions[1] && calls > len(b)+3*len(a) {
+			t.Errorf("Expected the unchanged columns to be reused, got %d comparisons", calls)
+		}
+	}
+}
+
+// Reports the comparisons per diff against successive single-element edits
+func BenchmarkMatrixCache(b *testing.B) {
+	var a []byte = []byte(strings.Repeat("abcdefghij", 20))
+	var versions [][]byte
+	for k := 0; k < 20; k++ {
+		var version []byte = append([]byte(nil), a...)
+		version[k*10+3] = 'X'
+		versions = append(versions, version)
+	}
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%v", cached), func(b *testing.B) {
+			var calls int
+			var equal = func(x, y byte) bool {
+				calls++
+				return x == y
+			}
+			var cache *MatrixCache[byte] = NewMatrixCache(a, equal)
+			for n := 0; n < b.N; n++ {
+				var version []byte = versions[n%len(versions)]
+				if cached {
+					cache.Diff(version)
+				} else {
+					Config{}.Diff(WithEqual(len(a), len(version), func(i, j int) bool {
+						return equal(a[i], version[j])
+					}))
+				}
+			}
+			b.ReportMetric(float64(calls)/float64(b.N), "comparisons/op")
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"errors"
+)
+
+// ErrBufferLimit is returned when a channel yields more elements than allowed
+var ErrBufferLimit = errors.New("diff: channel exceeded the buffer limit")
+
+// Reads the two channels until both are closed and diffs their elements.
+// As the algorithm needs random access, the elements are buffered: both
+// channels are read concurrently, so that neither producer blocks the other,
+// and ErrBufferLimit is returned as soon as either one yields more than
+// bufferLimit elements. The channels that are still open are then drained
+// in the background, discarding their elements so that the producers are
+// not left blocked on sending. Each drain ends when its channel is closed,
+// so a producer that never closes its channel keeps its drain running.
+// A bufferLimit of zero or less buffers everything: both channels are
+// then fully consumed before diffing and no error is returned,
+// as DiffChannelsDrained does.
+// A nil channel is treated as an empty, closed one.
+func DiffChannels[T comparable](a, b <-chan T, bufferLimit int) (Delta, error) {
+	var bufA, bufB []T
+	for a != nil || b != nil {
+		select {
+		case v, ok := <-a:
+			if !ok {
+				a = nil
+				continue
+			}
+			bufA = append(bufA, v)
+		case v, ok := <-b:
+			if !ok {
+				b = nil
+				continue
+			}
+			bufB = append(bufB, v)
+		}
+		if bufferLimit > 0 && (len(bufA) > bufferLimit || len(bufB) > bufferLimit) {
+			// Closed channels are nil and need no draining
+			if a != nil {
+				go drain(a)
+			}
+			if b != nil {
+				go drain(b)
+			}
+			return Delta{}, ErrBufferLimit
+		}
+	}
+	return Diff(WithEqual(len(bufA), len(bufB), func(i, j int) bool {
+		return bufA[i] == bufB[j]
+	})), nil
+}
+
+// Reads the two channels until both are closed and diffs their elements.
+// Both channels are fully consumed before diffing, concurrently so that
+// neither producer blocks the other, and all of their elements are
+// buffered, as the algorithm needs random access. A nil channel is
+// treated as an empty, closed one. This is DiffChannels without a limit.
+func DiffChannelsDrained[T comparable](a, b <-chan T) Delta {
+	var delta, _ = DiffChannels(a, b, 0)
+	return delta
+}
+
+// Reads and discards the elements of the channel until it is closed
+func drain[T any](c <-chan T) {
+	for range c {
+	}
+}
@@ -0,0 +1,109 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns a channel that yields the runes of the string
+func feed(s string) <-chan rune {
+	var result chan rune = make(chan rune, len(s))
+	go func() {
+		for _, r := range s {
+			result <- r
+		}
+		close(result)
+	}()
+	return result
+}
+
+func TestDiffChannels(t *testing.T) {
+	delta, err := DiffChannels(feed("abcdefgh"), feed("abbcedfh"), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var expected Delta = Delta{
+		Added:   []Mark{Mark{2, 3}, Mark{5, 6}},
+		Removed: []Mark{Mark{3, 4}, Mark{6, 7}},
+	}
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta %v, expected %v", delta, expected)
+	}
+
+	if _, err := DiffChannels(feed("abc"), feed("abcdefgh"), 5); err != ErrBufferLimit {
+		t.Errorf("Expected a buffer limit error, got %v", err)
+	}
+}
+
+func TestDiffChannelsDrained(t *testing.T) {
+	var long string = strings.Repeat("abcdefgh", 100)
+	var delta Delta = DiffChannelsDrained(feed(long), feed(long+"x"))
+	if expected := (Delta{Added: []Mark{Mark{800, 801}}}); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta %v, expected %v", delta, expected)
+	}
+
+	// Closed and nil channels are empty
+	var closed chan rune = make(chan rune)
+	close(closed)
+	if delta = DiffChannelsDrained(closed, nil); len(delta.Added) != 0 || len(delta.Removed) != 0 {
+		t.Errorf("Expected an empty delta for empty channels, got %v", delta)
+	}
+	if delta = DiffChannelsDrained(nil, feed("ab")); fmt.Sprintf("%v", delta) != "{[{0 2}] []}" {
+		t.Errorf("Expected an addition for a nil first channel, got %v", delta)
+	}
+
+	// A limit of zero or less is the same as no limit
+	if limited, err := DiffChannels(feed(long), feed(long+"x"), 0); err != nil || fmt.Sprint(limited) != fmt.Sprint(DiffChannelsDrained(feed(long), feed(long+"x"))) {
+		t.Errorf("Unexpected result without a limit: %v %v", limited, err)
+	}
+}
+
+func TestDiffChannelsLimitDrained(t *testing.T) {
+	// An unbuffered producer finishes only if all of its elements are read
+	var done chan bool = make(chan bool)
+	var unbuffered chan rune = make(chan rune)
+	go func() {
+		for _, r := range strings.Repeat("x", 100) {
+			unbuffered <- r
+		}
+		close(unbuffered)
+		close(done)
+	}()
+
+	if _, err := DiffChannels(unbuffered, feed("abc"), 5); err != ErrBufferLimit {
+		t.Fatalf("Expected a buffer limit error, got %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the producer to finish after the buffer limit error")
+	}
+}
+
+func TestDiffChannelsClosedNotDrained(t *testing.T) {
+	var before int = runtime.NumGoroutine()
+	for n := 0; n < 10; n++ {
+		var closed chan rune = make(chan rune)
+		close(closed)
+		var open chan rune = make(chan rune, 10)
+		for k := 0; k < 10; k++ {
+			open <- 'x'
+		}
+		close(open)
+		if _, err := DiffChannels(closed, open, 5); err != ErrBufferLimit {
+			t.Fatalf("Expected a buffer limit error, got %v", err)
+		}
+	}
+
+	// The drains of the open channels end once these are read to their end
+	var deadline time.Time = time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Unexpected goroutines left running\nGot %d\nExpected %d", after, before)
+	}
+}
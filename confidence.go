@@ -0,0 +1,37 @@
+package diff // import "github.com/spaskalev/diff"
+
+// ConfidenceInterface extends Interface for sequences whose
+// elements are matched with a varying degree of certainty
+type ConfidenceInterface interface {
+	Interface
+	// Returns the confidence that the elements at those indices
+	// are equal, only called for elements that Equal reports as such
+	Confidence(i, j int) float64
+}
+
+// A ConfidentMatch struct is a run of common elements along with
+// the average confidence of the equality of its element pairs
+type ConfidentMatch struct {
+	Common
+	Confidence float64
+}
+
+// Diffs the provided data and returns the delta along with
+// the runs of elements that it keeps, in order, each annotated
+// with its average confidence so that weak matches can be filtered.
+func DiffConfident(data ConfidenceInterface) (Delta, []ConfidentMatch) {
+	var len1, len2 = data.Len()
+	var delta Delta = Diff(data)
+	var result []ConfidentMatch
+	for _, s := range delta.segments(len1, len2) {
+		if s.Kind != Equal {
+			continue
+		}
+		var sum float64
+		for k := 0; k < s.Length; k++ {
+			sum += data.Confidence(s.FromX+k, s.FromY+k)
+		}
+		result = append(result, ConfidentMatch{Common{s.FromX, s.FromY, s.Length}, sum / float64(s.Length)})
+	}
+	return delta, result
+}
@@ -0,0 +1,52 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// Tokens that are equal with full confidence, except for
+// the ones a classifier matched with a lower score
+type scored struct {
+	a, b string
+	low  map[[2]int]float64
+}
+
+func (s scored) Len() (int, int) {
+	return len(s.a), len(s.b)
+}
+
+func (s scored) Equal(i, j int) bool {
+	return s.a[i] == s.b[j]
+}
+
+func (s scored) Confidence(i, j int) float64 {
+	if c, ok := s.low[[2]int{i, j}]; ok {
+		return c
+	}
+	return 1
+}
+
+func TestDiffConfident(t *testing.T) {
+	var data scored = scored{"abcxde", "abcyde", map[[2]int]float64{{3, 3}: 0, {4, 4}: 0.2, {5, 5}: 0.4}}
+
+	delta, matches := DiffConfident(data)
+	if fmt.Sprint(delta) != fmt.Sprint(Diff(data)) {
+		t.Errorf("Expected the delta to match Diff, got %v", delta)
+	}
+
+	var expected []ConfidentMatch = []ConfidentMatch{
+		{Common{0, 0, 3}, 1},
+		// The low confidence match is reported with its average score
+		{Common{4, 4, 2}, 0.3},
+	}
+	if len(matches) != len(expected) {
+		t.Fatalf("Unexpected matches\nGot %v\nExpected %v", matches, expected)
+	}
+	for k := range matches {
+		if matches[k].Common != expected[k].Common || math.Abs(matches[k].Confidence-expected[k].Confidence) > 1e-9 {
+			t.Errorf("Unexpected match\nGot %v\nExpected %v", matches[k], expected[k])
+		}
+	}
+}
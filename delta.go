@@ -0,0 +1,649 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	bits "github.com/spaskalev/bits"
+)
+
+// Returns the change regions of the delta in sequence order. Each region
+// is a box spanning the removed range [x, lenX) in the first sequence
+// and the added range [y, lenY) in the second one, either of which may be empty.
+// Marks that touch each other are merged into a single region.
+func (d Delta) changes() []box {
+	var result []box
+	var x, y, r, a int
+	for r < len(d.Removed) || a < len(d.Added) {
+		// Skip the unchanged run that precedes the closest mark
+		var skip int
+		if r < len(d.Removed) {
+			skip = d.Removed[r].From - x
+		}
+		if a < len(d.Added) && (r == len(d.Removed) || d.Added[a].From-y < skip) {
+			skip = d.Added[a].From - y
+		}
+		x, y = x+skip, y+skip
+
+		var current box = box{point{x, y}, x, y}
+		for {
+			if r < len(d.Removed) && d.Removed[r].From == current.lenX {
+				current.lenX = d.Removed[r].Length
+				r++
+			} else if a < len(d.Added) && d.Added[a].From == current.lenY {
+				current.lenY = d.Added[a].Length
+				a++
+			} else {
+				break
+			}
+		}
+		result = append(result, current)
+		x, y = current.lenX, current.lenY
+	}
+	return result
+}
+
+// Returns the total number of elements covered by the marks
+func count(marks []Mark) int {
+	var result int
+	for _, m := range marks {
+		result += m.Length - m.From
+	}
+	return result
+}
+
+// Returns the number of removed and added elements in the delta
+func (d Delta) Len() (int, int) {
+	return count(d.Removed), count(d.Added)
+}
+
+// Diffs the provided data and returns a delta with at most maxMarks marks,
+// along with the number of further marks that were left out. The included
+// marks are the leading ones in sequence order, so the delta describes
+// the changes up to the cut-off correctly but not the ones that follow it.
+func DiffCapped(data Interface, maxMarks int) (Delta, int) {
+	var delta Delta = Diff(data)
+	var total int = len(delta.Removed) + len(delta.Added)
+	if total <= maxMarks {
+		return delta, 0
+	}
+
+	var capped Delta
+	for _, c := range delta.changes() {
+		// A region removes before it adds, so its removal is kept first
+		if c.lenX > c.x && len(capped.Removed)+len(capped.Added) < maxMarks {
+			capped.Removed = append(capped.Removed, Mark{c.x, c.lenX})
+		}
+		if c.lenY > c.y && len(capped.Removed)+len(capped.Added) < maxMarks {
+			capped.Added = append(capped.Added, Mark{c.y, c.lenY})
+		}
+	}
+	return capped, total - len(capped.Removed) - len(capped.Added)
+}
+
+// A Replacement struct describes a changed region by both of its sides:
+// the removed range in the first sequence and the added range in the second.
+// Either side may be empty for pure insertions and deletions.
+type Replacement struct {
+	RemovedFrom, RemovedLen int
+	AddedFrom, AddedLen     int
+}
+
+// Returns the delta's changes as replacements in sequence order,
+// pairing each removal with the addition at the same position
+func Replacements(d Delta) []Replacement {
+	var result []Replacement
+	for _, c := range d.changes() {
+		result = append(result, Replacement{c.x, c.lenX - c.x, c.y, c.lenY - c.y})
+	}
+	return result
+}
+
+// Appends the range [from, to) to the marks, extending the last mark if they touch
+func appendMark(marks []Mark, from, to int) []Mark {
+	if n := len(marks); n > 0 && marks[n-1].Length == from {
+		marks[n-1].Length = to
+		return marks
+	}
+	return append(marks, Mark{from, to})
+}
+
+// Returns the number of added elements that fall into each of buckets equal
+// bins spanning the second sequence of the given length, e.g. for rendering
+// a strip that shows where the changes concentrate
+func (d Delta) Heatmap(buckets int, length int) []int {
+	if buckets <= 0 || length <= 0 {
+		return nil
+	}
+	var result []int = make([]int, buckets)
+	for _, m := range d.Added {
+		for p := m.From; p < m.Length && p < length; p++ {
+			result[p*buckets/length]++
+		}
+	}
+	return result
+}
+
+// A Kind tells whether a part of a diff is kept, removed or added
+type Kind int
+
+const (
+	// Present in both sequences
+	Equal Kind = iota
+	// Present in the first sequence only
+	Removed
+	// Present in the second sequence only
+	Added
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Equal:
+		return "equal"
+	case Removed:
+		return "removed"
+	case Added:
+		return "added"
+	}
+	return "unknown"
+}
+
+// A DiffKind classifies a delta as a whole
+type DiffKind int
+
+const (
+	// Nothing is added or removed
+	Identical DiffKind = iota
+	// Elements are only added
+	InsertOnly
+	// Elements are only removed
+	DeleteOnly
+	// Elements are both added and removed
+	Mixed
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case Identical:
+		return "identical"
+	case InsertOnly:
+		return "insert-only"
+	case DeleteOnly:
+		return "delete-only"
+	case Mixed:
+		return "mixed"
+	}
+	return "unknown"
+}
+
+// Returns whether the delta adds elements, removes them, does both or neither
+func (d Delta) Kind() DiffKind {
+	switch {
+	case len(d.Added) == 0 && len(d.Removed) == 0:
+		return Identical
+	case len(d.Removed) == 0:
+		return InsertOnly
+	case len(d.Added) == 0:
+		return DeleteOnly
+	}
+	return Mixed
+}
+
+// Returns the alignment of the elements that the delta keeps: the index
+// in the second sequence of each element in the first and vice versa,
+// or -1 for removed and added elements respectively
+func (d Delta) align(len1, len2 int) (aToB []int, bToA []int) {
+	aToB, bToA = make([]int, len1), make([]int, len2)
+	for i := range aToB {
+		aToB[i] = -1
+	}
+	for j := range bToA {
+		bToA[j] = -1
+	}
+
+	var x, y int
+	var keep = func(toX, toY int) {
+		for ; x < toX && y < toY; x, y = x+1, y+1 {
+			aToB[x], bToA[y] = y, x
+		}
+	}
+	for _, c := range d.changes() {
+		keep(c.x, c.y)
+		x, y = c.lenX, c.lenY
+	}
+	keep(len1, len2)
+	return
+}
+
+// Returns a delta that removes the elements of the first sequence
+// and adds the elements of the second one that are not aligned
+func fromAlignment(aToB []int, bToA []int) Delta {
+	var result Delta
+	for i, j := range aToB {
+		if j < 0 {
+			result.Removed = appendMark(result.Removed, i, i+1)
+		}
+	}
+	for j, i := range bToA {
+		if i < 0 {
+			result.Added = appendMark(result.Added, j, j+1)
+		}
+	}
+	return result
+}
+
+// Composes successive deltas into a single one. The delta at index k
+// transforms the sequence with lengths[k] elements into the one with
+// lengths[k+1] elements, so there must be one more length than deltas.
+// An element of the first sequence is kept by the composite
+// only if every delta keeps it along the way.
+func Compose(deltas []Delta, lengths []int) (Delta, error) {
+	if len(lengths) != len(deltas)+1 {
+		return Delta{}, fmt.Errorf("diff: %d deltas require %d lengths, got %d", len(deltas), len(deltas)+1, len(lengths))
+	}
+
+	var aToX, xToA []int = make([]int, lengths[0]), make([]int, lengths[0])
+	for i := range aToX {
+		aToX[i], xToA[i] = i, i
+	}
+	for k, d := range deltas {
+		if err := checkMarks(d.Removed, lengths[k], 0); err != nil {
+			return Delta{}, err
+		}
+		if err := checkMarks(d.Added, lengths[k+1], 1); err != nil {
+			return Delta{}, err
+		}
+		if lengths[k]-count(d.Removed) != lengths[k+1]-count(d.Added) {
+			return Delta{}, &LengthError{lengths[k] - count(d.Removed), lengths[k+1] - count(d.Added)}
+		}
+
+		var xToY, yToX = d.align(lengths[k], lengths[k+1])
+		for i, x := range aToX {
+			if x >= 0 {
+				aToX[i] = xToY[x]
+			}
+		}
+		var yToA []int = make([]int, len(yToX))
+		for y, x := range yToX {
+			yToA[y] = -1
+			if x >= 0 {
+				yToA[y] = xToA[x]
+			}
+		}
+		xToA = yToA
+	}
+	return fromAlignment(aToX, xToA), nil
+}
+
+// Groups the delta's changes into change-sets: changes that are
+// separated by at most gap unchanged elements belong to the same set.
+// Each change-set is returned as a delta holding only its own marks.
+func (d Delta) ChangeSets(gap int) []Delta {
+	var result []Delta
+	var previous box
+	for i, c := range d.changes() {
+		if i == 0 || c.x-previous.lenX > gap {
+			result = append(result, Delta{})
+		}
+		var current *Delta = &result[len(result)-1]
+		if c.lenX > c.x {
+			current.Removed = append(current.Removed, Mark{c.x, c.lenX})
+		}
+		if c.lenY > c.y {
+			current.Added = append(current.Added, Mark{c.y, c.lenY})
+		}
+		previous = c
+	}
+	return result
+}
+
+// Returns the smallest ranges of both sequences that contain all changes,
+// as a start and a length in the first and in the second sequence.
+// A delta without changes yields empty ranges at the start.
+func (d Delta) BoundingChange() (aFrom, aLen, bFrom, bLen int) {
+	var changes []box = d.changes()
+	if len(changes) == 0 {
+		return
+	}
+	var first, last box = changes[0], changes[len(changes)-1]
+	return first.x, last.lenX - first.x, first.y, last.lenY - first.y
+}
+
+// A Segment struct is a run of Length elements that are either kept,
+// removed or added, starting at FromX in the first sequence and at FromY
+// in the second. For removed and added runs the offset in the other
+// sequence is the position where the run would be in it.
+type Segment struct {
+	Kind         Kind
+	FromX, FromY int
+	Length       int
+}
+
+// Diffs the provided data and returns it as a list of segments that tile
+// both sequences, including the unchanged runs. Equal and removed segments
+// cover the first sequence, equal and added segments cover the second one,
+// each index exactly once and in order.
+func DiffWithContext(data Interface) []Segment {
+	var len1, len2 = data.Len()
+	return Diff(data).segments(len1, len2)
+}
+
+// Returns the delta as segments that tile sequences of the given lengths
+func (d Delta) segments(len1, len2 int) []Segment {
+	var result []Segment
+	var x, y int
+	for _, c := range d.changes() {
+		if c.x > x {
+			result = append(result, Segment{Equal, x, y, c.x - x})
+		}
+		if c.lenX > c.x {
+			result = append(result, Segment{Removed, c.x, c.y, c.lenX - c.x})
+		}
+		if c.lenY > c.y {
+			result = append(result, Segment{Added, c.lenX, c.y, c.lenY - c.y})
+		}
+		x, y = c.lenX, c.lenY
+	}
+	if len1 > x {
+		result = append(result, Segment{Equal, x, y, len1 - x})
+	}
+	return result
+}
+
+// An RLEOp struct is a run-length encoded edit operation:
+// keep, remove or add the next Count elements
+type RLEOp struct {
+	Kind  Kind
+	Count int
+}
+
+// Returns the delta as a run-length encoded stream of operations that
+// transforms a first sequence of length lenA into the second one,
+// with the unchanged runs in between the marks made explicit.
+// Within each changed region removals precede additions.
+func (d Delta) Ops(lenA int) []RLEOp {
+	var result []RLEOp
+	for _, s := range d.segments(lenA, 0) {
+		result = append(result, RLEOp{s.Kind, s.Length})
+	}
+	return result
+}
+
+// Translates positions in the first sequence to the corresponding positions
+// in the second one after applying the delta, e.g. to keep bookmarks in place
+// across an edit. A kept element's position maps to where the element ends up.
+// A removed element's position maps to the start of the range that replaces
+// its changed region in the second sequence, i.e. to the first added element
+// or, for a pure removal, to the element that follows the removed range.
+// Positions at or past the end of the first sequence are shifted along with it.
+func (d Delta) MapPositions(positions []int) []int {
+	var changes []box = d.changes()
+	var result []int = make([]int, len(positions))
+	for i, p := range positions {
+		var k int = sort.Search(len(changes), func(k int) bool {
+			return changes[k].lenX > p
+		})
+		switch {
+		case k < len(changes) && changes[k].x <= p:
+			result[i] = changes[k].y
+		case k > 0:
+			result[i] = p - changes[k-1].lenX + changes[k-1].lenY
+		default:
+			result[i] = p
+		}
+	}
+	return result
+}
+
+// Estimates the number of bytes needed to store the delta as a patch
+// that carries the added elements, each taking elementSize bytes.
+// The marks are assumed to be stored compactly as variable-length
+// integers, each as the gap that precedes it and its length,
+// preceded by the number of marks on either side.
+func (d Delta) EstimateSize(elementSize int) int {
+	var size int = uvarintSize(len(d.Removed)) + uvarintSize(len(d.Added))
+	for _, marks := range [][]Mark{d.Removed, d.Added} {
+		var end int
+		for _, m := range marks {
+			size += uvarintSize(m.From-end) + uvarintSize(m.Length-m.From)
+			end = m.Length
+		}
+	}
+	return size + count(d.Added)*elementSize
+}
+
+// Returns the number of bytes in the variable-length encoding of a non-negative value
+func uvarintSize(value int) int {
+	var size int = 1
+	for ; value >= 0x80; value >>= 7 {
+		size++
+	}
+	return size
+}
+
+// A Transition struct tracks a single element across the delta by its index
+// in the first and in the second sequence. OldIndex is -1 for an added
+// element and NewIndex is -1 for a removed one.
+type Transition struct {
+	OldIndex, NewIndex int
+}
+
+// Returns a transition for every element of sequences with the given lengths
+// in sequence order, e.g. for animating the changes of a list: kept elements
+// move from their old to their new index, removed ones leave and added ones
+// enter. Within each changed region removals precede additions.
+func (d Delta) Transitions(lenA, lenB int) []Transition {
+	var result []Transition = make([]Transition, 0, max(lenA, lenB))
+	for _, s := range d.segments(lenA, lenB) {
+		for k := 0; k < s.Length; k++ {
+			switch s.Kind {
+			case Equal:
+				result = append(result, Transition{s.FromX + k, s.FromY + k})
+			case Removed:
+				result = append(result, Transition{s.FromX + k, -1})
+			case Added:
+				result = append(result, Transition{-1, s.FromY + k})
+			}
+		}
+	}
+	return result
+}
+
+// A PositionedMark struct is a mark along with whether it is removed
+// from the first sequence or added to the second one
+type PositionedMark struct {
+	Mark
+	Kind Kind
+}
+
+// Returns the removed and added marks merged into a single stream in sequence
+// order. Removed marks are positioned in the first sequence and added ones
+// in the second, so both are ordered by the offset at which they follow the
+// unchanged elements that precede them, i.e. as they would be encountered
+// while walking both sequences in step. A removal and an addition at the
+// same place, e.g. a replacement, are ordered with the removal first.
+func (d Delta) Ordered() []PositionedMark {
+	var result []PositionedMark = make([]PositionedMark, 0, len(d.Removed)+len(d.Added))
+	var x, y, r, a int
+	for r < len(d.Removed) || a < len(d.Added) {
+		if a == len(d.Added) || (r < len(d.Removed) && d.Removed[r].From-x <= d.Added[a].From-y) {
+			var m Mark = d.Removed[r]
+			x, y = m.Length, y+m.From-x
+			result = append(result, PositionedMark{m, Removed})
+			r++
+		} else {
+			var m Mark = d.Added[a]
+			x, y = x+m.From-y, m.Length
+			result = append(result, PositionedMark{m, Added})
+			a++
+		}
+	}
+	return result
+}
+
+// Returns a stable 64-bit FNV-1a hash of the delta, e.g. for caching and
+// deduplicating deltas. The hash is computed over the canonical form of the
+// marks, so deltas that differ only in how their marks are fragmented,
+// e.g. {0, 2} versus {0, 1} and {1, 2}, or in their order hash equally.
+func (d Delta) Hash() uint64 {
+	var hash = fnv.New64a()
+	var buffer [binary.MaxVarintLen64]byte
+	for _, marks := range [][]Mark{canonical(d.Removed), canonical(d.Added)} {
+		hash.Write(buffer[:binary.PutUvarint(buffer[:], uint64(len(marks)))])
+		for _, m := range marks {
+			hash.Write(buffer[:binary.PutUvarint(buffer[:], uint64(m.From))])
+			hash.Write(buffer[:binary.PutUvarint(buffer[:], uint64(m.Length))])
+		}
+	}
+	return hash.Sum64()
+}
+
+// Returns the marks sorted, with empty ones dropped and touching ones merged
+func canonical(marks []Mark) []Mark {
+	var sorted []Mark = append([]Mark(nil), marks...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].From < sorted[j].From
+	})
+	var result []Mark
+	for _, m := range sorted {
+		if m.Length > m.From {
+			result = appendMark(result, m.From, m.Length)
+		}
+	}
+	return result
+}
+
+// Returns where the element at bIndex in the second sequence comes from:
+// the index of the same element in the first sequence if the delta keeps it,
+// or true for added if the delta adds it, with aIndex being -1. Indices
+// outside the second sequence yield -1 and false. Each call prepares
+// the lookup in linear time, so many lookups are better served
+// by a single Origins, which answers each in O(log n) time.
+func (d Delta) OriginOf(bIndex, lenA, lenB int) (aIndex int, added bool) {
+	return d.Origins(lenA, lenB).OriginOf(bIndex)
+}
+
+// An Origins struct looks up where the elements of the second sequence
+// of a delta come from, see Delta.OriginOf
+type Origins struct {
+	changes    []box
+	lenA, lenB int
+}
+
+// Prepares the lookup of the origins of the elements of the second
+// sequence, of length lenB, in the first one, of length lenA
+func (d Delta) Origins(lenA, lenB int) Origins {
+	return Origins{d.changes(), lenA, lenB}
+}
+
+// Returns where the element at bIndex in the second sequence comes from,
+// as Delta.OriginOf does, in O(log n) time in the number of changes
+func (o Origins) OriginOf(bIndex int) (aIndex int, added bool) {
+	if bIndex < 0 || bIndex >= o.lenB {
+		return -1, false
+	}
+	var k int = sort.Search(len(o.changes), func(k int) bool {
+		return o.changes[k].lenY > bIndex
+	})
+	if k < len(o.changes) && o.changes[k].y <= bIndex {
+		return -1, true
+	}
+	aIndex = bIndex
+	if k > 0 {
+		aIndex = bIndex - o.changes[k-1].lenY + o.changes[k-1].lenX
+	}
+	if aIndex >= o.lenA {
+		return -1, false
+	}
+	return aIndex, false
+}
+
+// Returns a delta with at most n change regions, i.e. hunks without context,
+// merging the regions that are separated by the smallest unchanged gaps
+// first, the leftmost ones on ties. Merged regions remove and re-add the
+// gaps between them, so the result is still a valid transform between the
+// same sequences, just a coarser one. An n less than one is treated as one.
+func (d Delta) LimitHunks(n int) Delta {
+	var changes []box = d.changes()
+	n = max(n, 1)
+	if len(changes) <= n {
+		return d
+	}
+
+	var gaps []int = make([]int, len(changes)-1)
+	for k := range gaps {
+		gaps[k] = k
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return changes[gaps[i]+1].x-changes[gaps[i]].lenX < changes[gaps[j]+1].x-changes[gaps[j]].lenX
+	})
+	var merged []bool = make([]bool, len(gaps))
+	for _, k := range gaps[:len(changes)-n] {
+		merged[k] = true
+	}
+
+	var result Delta
+	var current box = changes[0]
+	for k := 1; k <= len(changes); k++ {
+		if k < len(changes) && merged[k-1] {
+			current.lenX, current.lenY = changes[k].lenX, changes[k].lenY
+			continue
+		}
+		if current.lenX > current.x {
+			result.Removed = append(result.Removed, Mark{current.x, current.lenX})
+		}
+		if current.lenY > current.y {
+			result.Added = append(result.Added, Mark{current.y, current.lenY})
+		}
+		if k < len(changes) {
+			current = changes[k]
+		}
+	}
+	return result
+}
+
+// Returns for each index of the first sequence, of length lenA, the index
+// of the same element in the second sequence, or -1 if the delta removes it
+func (d Delta) IndexMapAToB(lenA int) []int {
+	var removed, added = d.Len()
+	var aToB, _ = d.align(lenA, lenA-removed+added)
+	return aToB
+}
+
+// Returns for each index of the second sequence, of length lenB, the index
+// of the same element in the first sequence, or -1 if the delta adds it.
+// The two maps come from the same alignment and are inverses on the kept
+// elements: IndexMapAToB(lenA)[i] == j exactly when IndexMapBToA(lenB)[j] == i.
+func (d Delta) IndexMapBToA(lenB int) []int {
+	var removed, added = d.Len()
+	var _, bToA = d.align(lenB-added+removed, lenB)
+	return bToA
+}
+
+// Returns a bit vector over the second sequence, of length lenB,
+// with the bits of the elements that the delta adds set
+func (d Delta) AddedBitmap(lenB int) bits.Vector {
+	return bitmap(d.Added, lenB)
+}
+
+// Returns a bit vector over the first sequence, of length lenA,
+// with the bits of the elements that the delta removes set
+func (d Delta) RemovedBitmap(lenA int) bits.Vector {
+	return bitmap(d.Removed, lenA)
+}
+
+// Returns a bit vector of the given length with the marked bits set,
+// ignoring the parts of marks that fall outside of it. As the vector
+// is allocated in whole words, its Len may exceed the length,
+// with the bits past it unset.
+func bitmap(marks []Mark, length int) bits.Vector {
+	var result bits.Vector = bits.NewBit(uint(length))
+	for _, m := range marks {
+		for p := max(m.From, 0); p < m.Length && p < length; p++ {
+			result.Poke(uint(p), true)
+		}
+	}
+	return result
+}
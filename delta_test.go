@@ -0,0 +1,511 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	bits "github.com/spaskalev/bits"
+)
+
+func TestDiffCapped(t *testing.T) {
+	var seq1, seq2 string = "abcdefgh", "abbcedfh"
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	tests := []struct {
+		max       int
+		delta     Delta
+		remaining int
+	}{
+		{0, Delta{}, 4},
+		{1, Delta{Added: []Mark{Mark{2, 3}}}, 3},
+		{2, Delta{Added: []Mark{Mark{2, 3}}, Removed: []Mark{Mark{3, 4}}}, 2},
+		{3, Delta{Added: []Mark{Mark{2, 3}, Mark{5, 6}}, Removed: []Mark{Mark{3, 4}}}, 1},
+		{4, Diff(data), 0},
+		{10, Diff(data), 0},
+	}
+
+	for _, testCase := range tests {
+		delta, remaining := DiffCapped(data, testCase.max)
+		if fmt.Sprintf("%v %d", delta, remaining) != fmt.Sprintf("%v %d", testCase.delta, testCase.remaining) {
+			t.Errorf("Unexpected result for %d marks\nGot %v %d\nExpected %v %d",
+				testCase.max, delta, remaining, testCase.delta, testCase.remaining)
+		}
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	data := []struct {
+		delta        Delta
+		replacements []Replacement
+	}{
+		{Delta{}, nil},
+		{Delta{Added: []Mark{Mark{2, 3}}}, []Replacement{Replacement{2, 0, 2, 1}}},
+		{Delta{Removed: []Mark{Mark{1, 4}}}, []Replacement{Replacement{1, 3, 1, 0}}},
+		{
+			Delta{Added: []Mark{Mark{2, 3}, Mark{5, 6}}, Removed: []Mark{Mark{3, 4}, Mark{6, 7}}},
+			[]Replacement{Replacement{2, 0, 2, 1}, Replacement{3, 1, 4, 0}, Replacement{5, 0, 5, 1}, Replacement{6, 1, 7, 0}},
+		},
+		{
+			Delta{Added: []Mark{Mark{1, 3}, Mark{6, 7}}, Removed: []Mark{Mark{1, 2}, Mark{4, 6}}},
+			[]Replacement{Replacement{1, 1, 1, 2}, Replacement{4, 2, 5, 0}, Replacement{7, 0, 6, 1}},
+		},
+	}
+
+	for _, testCase := range data {
+		if replacements := Replacements(testCase.delta); fmt.Sprintf("%v", replacements) != fmt.Sprintf("%v", testCase.replacements) {
+			t.Errorf("Unexpected replacements for %v\nGot %v\nExpected %v", testCase.delta, replacements, testCase.replacements)
+		}
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	data := []struct {
+		delta   Delta
+		buckets int
+		length  int
+		heatmap []int
+	}{
+		{Delta{}, 4, 8, []int{0, 0, 0, 0}},
+		{Delta{Added: []Mark{Mark{10, 16}, Mark{18, 19}}}, 4, 20, []int{0, 0, 5, 2}},
+		{Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{0, 5}}}, 2, 3, []int{1, 0}},
+		{Delta{Added: []Mark{Mark{0, 3}}}, 5, 3, []int{1, 1, 0, 1, 0}},
+		{Delta{Added: []Mark{Mark{0, 3}}}, 0, 3, nil},
+	}
+
+	for _, testCase := range data {
+		if heatmap := testCase.delta.Heatmap(testCase.buckets, testCase.length); fmt.Sprint(heatmap) != fmt.Sprint(testCase.heatmap) {
+			t.Errorf("Unexpected heatmap for %v in %d buckets\nGot %v\nExpected %v",
+				testCase.delta, testCase.buckets, heatmap, testCase.heatmap)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	var sequences []string = []string{"abcdefgh", "abXdefgYh", "aXdeZfgYhW"}
+	var deltas []Delta
+	var lengths []int = []int{len(sequences[0])}
+	for k := 1; k < len(sequences); k++ {
+		var from, to string = sequences[k-1], sequences[k]
+		deltas = append(deltas, Diff(WithEqual(len(from), len(to), func(i, j int) bool {
+			return from[i] == to[j]
+		})))
+		lengths = append(lengths, len(to))
+	}
+
+	composite, err := Compose(deltas, lengths)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var first, last string = sequences[0], sequences[len(sequences)-1]
+	if err := Verify(WithEqual(len(first), len(last), func(i, j int) bool {
+		return first[i] == last[j]
+	}), composite, nil); err != nil {
+		t.Errorf("Expected the composite %v to transform the original into the final sequence: %v", composite, err)
+	}
+	var expected Delta = Delta{
+		Added:   []Mark{Mark{1, 2}, Mark{4, 5}, Mark{7, 8}, Mark{9, 10}},
+		Removed: []Mark{Mark{1, 3}},
+	}
+	if fmt.Sprintf("%v", composite) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected composite\nGot %v\nExpected %v", composite, expected)
+	}
+
+	if _, err := Compose(deltas, lengths[:2]); err == nil {
+		t.Errorf("Expected an error for missing lengths")
+	}
+	if _, err := Compose(deltas, []int{8, 9, 11}); err == nil {
+		t.Errorf("Expected an error for mismatching lengths")
+	}
+}
+
+func TestChangeSets(t *testing.T) {
+	var delta Delta = Delta{
+		Added:   []Mark{Mark{2, 3}, Mark{5, 6}, Mark{40, 42}},
+		Removed: []Mark{Mark{3, 4}, Mark{39, 40}, Mark{43, 44}},
+	}
+
+	data := []struct {
+		gap  int
+		sets []Delta
+	}{
+		{1000, []Delta{delta}},
+		{3, []Delta{
+			Delta{Added: []Mark{Mark{2, 3}, Mark{5, 6}}, Removed: []Mark{Mark{3, 4}}},
+			Delta{Added: []Mark{Mark{40, 42}}, Removed: []Mark{Mark{39, 40}, Mark{43, 44}}},
+		}},
+		{0, []Delta{
+			Delta{Added: []Mark{Mark{2, 3}}},
+			Delta{Removed: []Mark{Mark{3, 4}}},
+			Delta{Added: []Mark{Mark{5, 6}}},
+			Delta{Added: []Mark{Mark{40, 42}}, Removed: []Mark{Mark{39, 40}}},
+			Delta{Removed: []Mark{Mark{43, 44}}},
+		}},
+	}
+
+	for _, testCase := range data {
+		if sets := delta.ChangeSets(testCase.gap); fmt.Sprintf("%v", sets) != fmt.Sprintf("%v", testCase.sets) {
+			t.Errorf("Unexpected change-sets for gap %d\nGot %v\nExpected %v", testCase.gap, sets, testCase.sets)
+		}
+	}
+	if sets := (Delta{}).ChangeSets(1); len(sets) != 0 {
+		t.Errorf("Expected no change-sets for an empty delta, got %v", sets)
+	}
+}
+
+func TestBoundingChange(t *testing.T) {
+	data := []struct {
+		delta    Delta
+		bounding [4]int
+	}{
+		{Delta{}, [4]int{0, 0, 0, 0}},
+		{Delta{Added: []Mark{Mark{3, 5}}}, [4]int{3, 0, 3, 2}},
+		{Delta{Removed: []Mark{Mark{3, 5}}}, [4]int{3, 2, 3, 0}},
+		{Delta{
+			Added:   []Mark{Mark{2, 3}, Mark{5, 6}},
+			Removed: []Mark{Mark{3, 4}, Mark{6, 7}},
+		}, [4]int{2, 5, 2, 5}},
+	}
+
+	for _, testCase := range data {
+		aFrom, aLen, bFrom, bLen := testCase.delta.BoundingChange()
+		if [4]int{aFrom, aLen, bFrom, bLen} != testCase.bounding {
+			t.Errorf("Unexpected bounding change for %v\nGot %v\nExpected %v",
+				testCase.delta, [4]int{aFrom, aLen, bFrom, bLen}, testCase.bounding)
+		}
+	}
+}
+
+func TestDiffWithContext(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+	}{
+		{"", ""},
+		{"abc", ""},
+		{"", "abc"},
+		{"abc", "abc"},
+		{"abcdefgh", "abbcedfh"},
+		{"abcdefgh", "XYcdeZ"},
+	}
+
+	for _, testCase := range data {
+		var segments []Segment = DiffWithContext(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}))
+
+		// Rebuild both sequences from the segments
+		var x, y int
+		var seq1, seq2 string
+		for _, s := range segments {
+			if s.Length <= 0 {
+				t.Errorf("Unexpected empty segment %v", s)
+			}
+			if (s.Kind != Added && s.FromX != x) || (s.Kind != Removed && s.FromY != y) {
+				t.Errorf("Unexpected segment %v at (%d, %d)", s, x, y)
+			}
+			switch s.Kind {
+			case Equal:
+				if testCase.seq1[x:x+s.Length] != testCase.seq2[y:y+s.Length] {
+					t.Errorf("Unexpected equal segment %v", s)
+				}
+				seq1, seq2 = seq1+testCase.seq1[x:x+s.Length], seq2+testCase.seq2[y:y+s.Length]
+				x, y = x+s.Length, y+s.Length
+			case Removed:
+				seq1, x = seq1+testCase.seq1[x:x+s.Length], x+s.Length
+			case Added:
+				seq2, y = seq2+testCase.seq2[y:y+s.Length], y+s.Length
+			}
+		}
+		if seq1 != testCase.seq1 || seq2 != testCase.seq2 {
+			t.Errorf("Expected the segments %v to tile [%s] and [%s]", segments, testCase.seq1, testCase.seq2)
+		}
+	}
+}
+
+func TestOps(t *testing.T) {
+	data := []struct {
+		delta Delta
+		lenA  int
+		ops   []RLEOp
+	}{
+		{Delta{}, 0, nil},
+		{Delta{}, 3, []RLEOp{{Equal, 3}}},
+		{Delta{Added: []Mark{Mark{5, 8}}, Removed: []Mark{Mark{5, 7}}}, 11, []RLEOp{{Equal, 5}, {Removed, 2}, {Added, 3}, {Equal, 4}}},
+		{Delta{
+			Added:   []Mark{Mark{2, 3}, Mark{5, 6}},
+			Removed: []Mark{Mark{3, 4}, Mark{6, 7}},
+		}, 8, []RLEOp{{Equal, 2}, {Added, 1}, {Equal, 1}, {Removed, 1}, {Equal, 1}, {Added, 1}, {Equal, 1}, {Removed, 1}, {Equal, 1}}},
+	}
+
+	for _, testCase := range data {
+		if ops := testCase.delta.Ops(testCase.lenA); fmt.Sprint(ops) != fmt.Sprint(testCase.ops) {
+			t.Errorf("Unexpected operations for %v\nGot %v\nExpected %v", testCase.delta, ops, testCase.ops)
+		}
+	}
+}
+
+func TestMapPositions(t *testing.T) {
+	// "abcdefgh" -> "aXbcfgYYh": adds X, replaces de by nothing, replaces nothing by YY
+	var delta Delta = Delta{
+		Added:   []Mark{Mark{1, 2}, Mark{6, 8}},
+		Removed: []Mark{Mark{3, 5}},
+	}
+	data := []struct {
+		positions []int
+		expected  []int
+	}{
+		{nil, []int{}},
+		// Before any edit
+		{[]int{0}, []int{0}},
+		// Shifted by the insertion
+		{[]int{1, 2}, []int{2, 3}},
+		// Inside the removed region
+		{[]int{3, 4}, []int{4, 4}},
+		// After the removal and around the last insertion
+		{[]int{5, 6, 7}, []int{4, 5, 8}},
+		// Past the end
+		{[]int{8}, []int{9}},
+	}
+
+	for _, testCase := range data {
+		if result := delta.MapPositions(testCase.positions); fmt.Sprint(result) != fmt.Sprint(testCase.expected) {
+			t.Errorf("Unexpected positions for %v\nGot %v\nExpected %v", testCase.positions, result, testCase.expected)
+		}
+	}
+}
+
+func TestEstimateSize(t *testing.T) {
+	var a, b []byte = []byte(strings.Repeat("abcdefgh", 40)), []byte(strings.Repeat("abcdXYgh", 30) + strings.Repeat("-", 200))
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))
+
+	// Serialize the delta with the added content
+	var buffer []byte
+	var put = func(value int) {
+		var encoded [binary.MaxVarintLen64]byte
+		buffer = append(buffer, encoded[:binary.PutUvarint(encoded[:], uint64(value))]...)
+	}
+	put(len(delta.Removed))
+	put(len(delta.Added))
+	for _, marks := range [][]Mark{delta.Removed, delta.Added} {
+		var end int
+		for _, m := range marks {
+			put(m.From - end)
+			put(m.Length - m.From)
+			end = m.Length
+		}
+	}
+	for _, m := range delta.Added {
+		buffer = append(buffer, b[m.From:m.Length]...)
+	}
+
+	var estimate int = delta.EstimateSize(1)
+	if diff := abs(estimate - len(buffer)); diff*10 > len(buffer) {
+		t.Errorf("Unexpected estimate %d for a patch of %d bytes", estimate, len(buffer))
+	}
+	if estimate := (Delta{}).EstimateSize(8); estimate != 2 {
+		t.Errorf("Unexpected estimate for an empty delta\nGot %d\nExpected 2", estimate)
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	var seq1, seq2 string = "abcd", "axcdy"
+	var delta Delta = Diff(WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	}))
+	var expected []Transition = []Transition{{0, 0}, {1, -1}, {-1, 1}, {2, 2}, {3, 3}, {-1, 4}}
+	if transitions := delta.Transitions(len(seq1), len(seq2)); fmt.Sprint(transitions) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected transitions\nGot %v\nExpected %v", transitions, expected)
+	}
+
+	if transitions := (Delta{}).Transitions(0, 0); len(transitions) != 0 {
+		t.Errorf("Expected no transitions for empty sequences, got %v", transitions)
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	// "abcdefghij" -> "aXbcfgYYhj": adds X, removes de, adds YY, removes i
+	var delta Delta = Delta{
+		Added:   []Mark{Mark{1, 2}, Mark{6, 8}},
+		Removed: []Mark{Mark{3, 5}, Mark{8, 9}},
+	}
+	var expected []PositionedMark = []PositionedMark{
+		{Mark{1, 2}, Added},
+		{Mark{3, 5}, Removed},
+		{Mark{6, 8}, Added},
+		{Mark{8, 9}, Removed},
+	}
+	if ordered := delta.Ordered(); fmt.Sprint(ordered) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected ordered marks\nGot %v\nExpected %v", ordered, expected)
+	}
+
+	// A replacement lists its removal first
+	delta = Delta{Added: []Mark{Mark{2, 3}}, Removed: []Mark{Mark{2, 4}}}
+	expected = []PositionedMark{{Mark{2, 4}, Removed}, {Mark{2, 3}, Added}}
+	if ordered := delta.Ordered(); fmt.Sprint(ordered) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected ordered marks for a replacement\nGot %v\nExpected %v", ordered, expected)
+	}
+}
+
+func TestHash(t *testing.T) {
+	var coalesced Delta = Delta{Added: []Mark{Mark{0, 3}}, Removed: []Mark{Mark{2, 4}, Mark{6, 7}}}
+	var fragmented Delta = Delta{Added: []Mark{Mark{0, 1}, Mark{1, 2}, Mark{2, 3}}, Removed: []Mark{Mark{6, 7}, Mark{2, 3}, Mark{3, 3}, Mark{3, 4}}}
+	if coalesced.Hash() != fragmented.Hash() {
+		t.Errorf("Expected equivalent deltas to hash equally, got %x and %x", coalesced.Hash(), fragmented.Hash())
+	}
+
+	var different []Delta = []Delta{
+		{},
+		{Added: []Mark{Mark{0, 3}}},
+		{Removed: []Mark{Mark{0, 3}}},
+		{Added: []Mark{Mark{0, 3}}, Removed: []Mark{Mark{2, 4}}},
+		{Added: []Mark{Mark{0, 3}}, Removed: []Mark{Mark{2, 4}, Mark{6, 8}}},
+		{Removed: []Mark{Mark{0, 3}, Mark{2, 4}, Mark{6, 7}}},
+	}
+	var seen map[uint64]Delta = map[uint64]Delta{coalesced.Hash(): coalesced}
+	for _, d := range different {
+		if previous, found := seen[d.Hash()]; found {
+			t.Errorf("Unexpected equal hashes for %v and %v", d, previous)
+		}
+		seen[d.Hash()] = d
+	}
+}
+
+func TestOriginOf(t *testing.T) {
+	var seq1, seq2 string = "abcdefgh", "aXbcfgYYh"
+	var delta Delta = Diff(WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	}))
+	var _, bToA = delta.align(len(seq1), len(seq2))
+
+	// Compare against the alignment, including indices out of bounds
+	var origins Origins = delta.Origins(len(seq1), len(seq2))
+	for j := -1; j <= len(seq2); j++ {
+		aIndex, added := delta.OriginOf(j, len(seq1), len(seq2))
+		if index, isAdded := origins.OriginOf(j); index != aIndex || isAdded != added {
+			t.Errorf("Unexpected prepared origin of %d\nGot %d %v\nExpected %d %v", j, index, isAdded, aIndex, added)
+		}
+		var expectedIndex int = -1
+		var expectedAdded bool
+		if j >= 0 && j < len(seq2) {
+			expectedIndex = bToA[j]
+			expectedAdded = expectedIndex < 0
+		}
+		if aIndex != expectedIndex || added != expectedAdded {
+			t.Errorf("Unexpected origin of %d\nGot %d %v\nExpected %d %v", j, aIndex, added, expectedIndex, expectedAdded)
+		}
+	}
+}
+
+func TestLimitHunks(t *testing.T) {
+	// Changes at 1, 4, 12 and 14 with gaps of 2, 7 and 1 unchanged elements
+	var seq1, seq2 string = "abcdefghijklmnop", "aXbcYefghijklMnPp"
+	var input Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+	var delta Delta = Diff(input)
+
+	data := []struct {
+		n       int
+		regions int
+	}{
+		{10, 4},
+		{4, 4},
+		{3, 3},
+		{2, 2},
+		{1, 1},
+		{0, 1},
+	}
+	for _, testCase := range data {
+		var limited Delta = delta.LimitHunks(testCase.n)
+		if regions := len(limited.changes()); regions != testCase.regions {
+			t.Errorf("Unexpected number of regions for %d\nGot %d (%v)\nExpected %d", testCase.n, regions, limited, testCase.regions)
+		}
+		if err := Verify(input, limited, nil); err != nil {
+			t.Errorf("Unexpected invalid delta %v for %d: %v", limited, testCase.n, err)
+		}
+	}
+
+	// The smallest gaps are merged first, the largest one remains
+	var expected Delta = Delta{Added: []Mark{Mark{1, 5}, Mark{13, 16}}, Removed: []Mark{Mark{1, 4}, Mark{12, 15}}}
+	if limited := delta.LimitHunks(2); fmt.Sprint(limited) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", limited, expected)
+	}
+}
+
+func TestDeltaKind(t *testing.T) {
+	data := []struct {
+		a, b     string
+		expected DiffKind
+	}{
+		{"", "", Identical},
+		{"abc", "abc", Identical},
+		{"ac", "abc", InsertOnly},
+		{"", "abc", InsertOnly},
+		{"abc", "ac", DeleteOnly},
+		{"abc", "", DeleteOnly},
+		{"abc", "axc", Mixed},
+	}
+	for _, testCase := range data {
+		var delta Delta = Diff(WithEqual(len(testCase.a), len(testCase.b), func(i, j int) bool {
+			return testCase.a[i] == testCase.b[j]
+		}))
+		if kind := delta.Kind(); kind != testCase.expected {
+			t.Errorf("Unexpected kind of %q and %q\nGot %v\nExpected %v", testCase.a, testCase.b, kind, testCase.expected)
+		}
+	}
+}
+
+func TestIndexMaps(t *testing.T) {
+	var seq1, seq2 string = "abcdefgh", "xbcdyfghz"
+	var delta Delta = Diff(WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	}))
+
+	var aToB, bToA []int = delta.IndexMapAToB(len(seq1)), delta.IndexMapBToA(len(seq2))
+	var expected []int = []int{-1, 1, 2, 3, -1, 5, 6, 7, -1}
+	if fmt.Sprint(bToA) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected map from B to A\nGot %v\nExpected %v", bToA, expected)
+	}
+	for i, j := range aToB {
+		if j >= 0 && bToA[j] != i {
+			t.Errorf("Unexpected inconsistent maps at %d\nGot %d\nExpected %d", j, bToA[j], i)
+		}
+	}
+	for j, i := range bToA {
+		if i >= 0 && aToB[i] != j {
+			t.Errorf("Unexpected inconsistent maps at %d\nGot %d\nExpected %d", i, aToB[i], j)
+		}
+	}
+}
+
+func TestBitmaps(t *testing.T) {
+	var delta Delta = Delta{Added: []Mark{Mark{0, 2}, Mark{5, 6}}, Removed: []Mark{Mark{3, 4}}}
+	data := []struct {
+		bitmap bits.Vector
+		marks  []Mark
+		length int
+	}{
+		{delta.AddedBitmap(7), delta.Added, 7},
+		{delta.RemovedBitmap(5), delta.Removed, 5},
+	}
+
+	for _, testCase := range data {
+		if testCase.bitmap.Len() < uint(testCase.length) {
+			t.Errorf("Unexpected bitmap length\nGot %d\nExpected at least %d", testCase.bitmap.Len(), testCase.length)
+		}
+		for p := 0; p < int(testCase.bitmap.Len()); p++ {
+			var marked bool
+			for _, m := range testCase.marks {
+				marked = marked || (m.From <= p && p < m.Length)
+			}
+			if testCase.bitmap.Peek(uint(p)) != marked {
+				t.Errorf("Unexpected bit %d of the bitmap for %v\nGot %v\nExpected %v", p, testCase.marks, testCase.bitmap.Peek(uint(p)), marked)
+			}
+		}
+	}
+}
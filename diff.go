@@ -8,6 +8,15 @@ import (
 
 // Interface abstracts the required knowledge to perform a diff
 // on any two fixed-length sequences with comparable elements.
+//
+// Elements are accessed through their indices only, so any structure with
+// random access can be diffed in place, e.g. a rope with O(log n) lookups.
+// Diff calls Len once and Equal exactly once for every pair of indices,
+// i.e. len2 times per element of the first sequence and len1 times per
+// element of the second. The calls are made row by row: all indices of the
+// second sequence for the first index of the first one, then the second
+// index and so on. Caching the current element of the first sequence
+// thus saves most of the lookups.
 type Interface interface {
 	// The sequences' lengths
 	Len() (int, int)
@@ -15,13 +24,15 @@ type Interface interface {
 	Equal(int, int) bool
 }
 
-// A Mark struct marks a length in sequence starting with an offset
+// A Mark struct marks a length in sequence starting with an offset.
+// Despite its name, Length holds the exclusive end offset of the range.
 type Mark struct {
 	From   int
 	Length int
 }
 
-// A Delta struct is the result of a Diff operation
+// A Delta struct is the result of a Diff operation.
+// It is safe to transport with encoding/gob, see GobEncode.
 type Delta struct {
 	Added   []Mark
 	Removed []Mark
@@ -30,17 +41,203 @@ type Delta struct {
 // Diffs the provided data and returns e Delta struct
 // with added entries' indices in the second sequence and removed from the first
 func Diff(data Interface) Delta {
+	return Config{}.Diff(data)
+}
+
+// The maximum number of differing positions for which
+// equal-length sequences are diffed position by position
+const maxPositional = 4
+
+// Diffs sequences of equal length that differ in only a few positions
+// by replacing each of these positions, which takes a linear scan instead
+// of filling the whole matrix. Reports false if the lengths differ or more
+// than maxPositional positions (or a quarter of them) differ. The result
+// need not be minimal, e.g. two swapped elements are both replaced
+// even though keeping one of them takes fewer edits, hence this
+// highlight only applies when Config.Positional is set.
+func positional(data Interface) (Delta, bool) {
 	var len1, len2 = data.Len()
-	var mx *matrix = &matrix{v: bits.NewBit(uint(len1 * len2)), lenX: len1, lenY: len2}
-	mx.matches = make(map[point]int)
+	if len1 != len2 {
+		return Delta{}, false
+	}
 
+	var result Delta
+	var differing int
 	for i := 0; i < len1; i++ {
-		for j := 0; j < len2; j++ {
-			mx.v.Poke(mx.at(point{i, j}), data.Equal(i, j))
+		if data.Equal(i, i) {
+			continue
+		}
+		if differing++; differing > maxPositional || 4*differing > len1 {
+			return Delta{}, false
+		}
+		result.Removed = appendMark(result.Removed, i, i+1)
+		result.Added = appendMark(result.Added, i, i+1)
+	}
+	return result, true
+}
+
+// A Pivot selects the common run on which the recursion splits a box
+type Pivot int
+
+const (
+	// Split on the first of the longest common runs in the box
+	Largest Pivot = iota
+	// Split on the longest common run that divides the box most evenly.
+	// This bounds the recursion depth to O(log n) on inputs with many
+	// runs of equal length, at the cost of considering all of them.
+	// As only the choice among equally long runs is affected the
+	// delta is of the same quality, although its marks may be placed differently.
+	Balanced
+)
+
+// A SplitScore rates a candidate common run of the given length that starts
+// at (x, y) in the region being diffed, which spans the removed range of the
+// first sequence and the added range of the second. The recursion splits
+// the region on the run with the highest score, preferring the first one
+// found on ties. Without a score the longest run is split on, which finds
+// large common blocks first but may recurse deeply on inputs made of many
+// short runs.
+type SplitScore func(x, y, length int, removed, added Mark) int
+
+// A SplitScore that weighs the length of a run by how evenly it splits
+// the region. A run in the middle of the region outweighs one at its edge
+// that is up to one and a half times longer, which keeps the recursion
+// balanced at the cost of sometimes splitting on a shorter run.
+func Centered(x, y, length int, removed, added Mark) int {
+	var size int = (removed.Length - removed.From) + (added.Length - added.From)
+	var before int = (x - removed.From) + (y - added.From)
+	var after int = size - before - 2*length
+	return length * (size + min(before, after))
+}
+
+// A Config struct tunes the diff algorithm. Its zero value
+// is the configuration used by the package-level Diff function.
+type Config struct {
+	// When set, sequences of equal length that differ in only a few
+	// positions are diffed with a linear scan that replaces each of these
+	// positions, instead of filling the match matrix. This highlights
+	// changed values exactly but is not minimal for shifted ones,
+	// e.g. swapped elements, which the regular diff partially keeps.
+	// Diff and the other package-level functions never take this path.
+	Positional bool
+	Pivot      Pivot
+	// When set, the common run with the highest score is split on
+	// instead of the one selected by the pivot, see SplitScore
+	Score SplitScore
+	// When positive, only matches within this many positions of the start
+	// of the currently diffed region are considered, see DiffWindowed
+	Window int
+	// When positive, the elements are compared on demand rather than upfront
+	// and the results of at most this many comparisons are kept, evicting
+	// the least recently used ones. This bounds the memory to O(CacheSize)
+	// instead of the O(len1*len2) bits of the match matrix, at the cost
+	// of comparing evicted pairs again when they are revisited, which
+	// may take many more calls to Equal for a small cache.
+	CacheSize int
+	// When positive, common runs shorter than this are not split on,
+	// see DiffMinMatch
+	MinMatch int
+	// When positive, matches between elements whose indices differ by more
+	// than this are ignored, e.g. to keep nearly synchronized streams from
+	// aligning an early element of one with a late element of the other.
+	// Unlike Window this bounds the absolute offset |i - j| of every match.
+	MaxDrift int
+	// When set, the common suffix of the sequences is matched upfront and
+	// only the rest is diffed, so that the tails of append-only data, e.g.
+	// logs, stay aligned however much their middles differ
+	AnchorTail bool
+}
+
+// Diffs the provided data considering only matches that are offset by at most
+// window positions from the start of the region being diffed. As the regions
+// follow the alignment found so far, the window is in sequence space: the two
+// sequences may drift apart arbitrarily, as long as each step is local. This
+// differs from a diagonal band, which bounds the absolute offset |i - j|
+// of every match. Differences that span more than the window are reported
+// as removals and additions.
+func DiffWindowed(data Interface, window int) Delta {
+	return Config{Window: window}.Diff(data)
+}
+
+// Diffs the provided data ignoring common runs shorter than minMatch, so that
+// coincidental matches of a few elements, e.g. a single space or brace, amid
+// otherwise different content are reported as part of the surrounding change
+// rather than splitting it. Runs are limited to the region being diffed,
+// so a longer run may still be cut short by a split elsewhere.
+func DiffMinMatch(data Interface, minMatch int) Delta {
+	return Config{MinMatch: minMatch}.Diff(data)
+}
+
+// Diffs only the first maxLen elements of each sequence, leaving the rest
+// out of scope, e.g. to compare the heads of huge files. The marks of the
+// delta are bounded by the truncated lengths and Equal is never called
+// for elements past them.
+func DiffPrefix(data Interface, maxLen int) Delta {
+	var len1, len2 = data.Len()
+	if maxLen < 0 {
+		maxLen = 0
+	}
+	return Diff(WithEqual(min(len1, maxLen), min(len2, maxLen), data.Equal))
+}
+
+// A Stats struct describes the shape of a diff's recursion
+type Stats struct {
+	// Number of recursive calls
+	Calls int
+	// Maximum depth of the recursion
+	Depth int
+}
+
+// Diffs the provided data using the configuration
+func (c Config) Diff(data Interface) Delta {
+	var delta, _ = c.DiffStats(data)
+	return delta
+}
+
+// Diffs the provided data using the configuration
+// and returns statistics about the recursion alongside the delta
+func (c Config) DiffStats(data Interface) (Delta, Stats) {
+	return c.diffStats(data, nil)
+}
+
+// Diffs the provided data using the configuration, giving up with
+// an incomplete delta as soon as stop, if set, reports true
+func (c Config) diffStats(data Interface, stop func() bool) (Delta, Stats) {
+	if c.Positional {
+		if delta, ok := positional(data); ok {
+			return delta, Stats{}
+		}
+	}
+
+	var len1, len2 = data.Len()
+	var end1, end2 int = len1, len2
+	if c.AnchorTail {
+		for end1 > 0 && end2 > 0 && data.Equal(end1-1, end2-1) {
+			end1, end2 = end1-1, end2-1
 		}
 	}
 
-	return mx.recursiveDiff(box{point{0, 0}, len1, len2})
+	var mx *matrix = c.matrix(len1, len2)
+	mx.stop = stop
+	if c.CacheSize > 0 {
+		mx.cache = newEqualityCache(data, c.CacheSize)
+	} else {
+		mx.fill(data, 0, end1)
+	}
+
+	var delta Delta = mx.recursiveDiff(box{point{0, 0}, end1, end2}, 1)
+	return delta, mx.stats
+}
+
+// Returns an empty match matrix for sequences with the given lengths.
+// With a bounded cache neither the matrix nor the run lengths are stored.
+func (c Config) matrix(len1, len2 int) *matrix {
+	if c.CacheSize > 0 {
+		return &matrix{v: bits.NewBit(0), lenX: len1, lenY: len2, pivot: c.Pivot, score: c.Score, window: c.Window, minMatch: c.MinMatch, maxDrift: c.MaxDrift}
+	}
+	var mx *matrix = &matrix{v: bits.NewBit(uint(len1 * len2)), lenX: len1, lenY: len2, pivot: c.Pivot, score: c.Score, window: c.Window, minMatch: c.MinMatch, maxDrift: c.MaxDrift}
+	mx.matches = make(map[point]int)
+	return mx
 }
 
 type point struct {
@@ -63,6 +260,24 @@ type matrix struct {
 	v          bits.Vector
 	lenX, lenY int
 	matches    map[point]int
+	pivot      Pivot
+	score      SplitScore
+	window     int
+	minMatch   int
+	maxDrift   int
+	stats      Stats
+	// When set, elements are compared on demand through the cache instead
+	// of being looked up in the bit vector
+	cache *equalityCache
+	// When set, the matches that the recursion splits on are recorded in order
+	collect bool
+	common  []match
+	// When set, every recursive call is recorded along with its parent's index
+	record bool
+	calls  []call
+	parent int
+	// When set, the diff is abandoned as soon as this reports true
+	stop func() bool
 }
 
 // Translates (x, y) to an absolute position on the bit vector
@@ -70,23 +285,58 @@ func (mx *matrix) at(p point) uint {
 	return uint(p.y + (p.x * mx.lenY))
 }
 
-func (mx *matrix) recursiveDiff(bounds box) Delta {
+// Reports whether the elements at the point are equal
+func (mx *matrix) equal(p point) bool {
+	if mx.cache != nil {
+		return mx.cache.equal(p)
+	}
+	return mx.v.Peek(mx.at(p))
+}
+
+// Reports whether the diff is to be abandoned
+func (mx *matrix) stopped() bool {
+	return mx.stop != nil && mx.stop()
+}
+
+// Fills the rows of the matrix in [from, to) by comparing the elements
+func (mx *matrix) fill(data Interface, from, to int) {
+	for i := from; i < to && !mx.stopped(); i++ {
+		for j := 0; j < mx.lenY; j++ {
+			mx.v.Poke(mx.at(point{i, j}), data.Equal(i, j))
+		}
+	}
+}
+
+func (mx *matrix) recursiveDiff(bounds box, depth int) Delta {
+	mx.stats.Calls++
+	if depth > mx.stats.Depth {
+		mx.stats.Depth = depth
+	}
+
+	if mx.stopped() {
+		return Delta{}
+	}
+
 	var m match = mx.largest(bounds)
+	if mx.record {
+		mx.calls = append(mx.calls, call{bounds, m, mx.parent})
+		defer func(parent int) { mx.parent = parent }(mx.parent)
+		mx.parent = len(mx.calls) - 1
+	}
 
 	if m.length == 0 { // Recursion terminates
-		var immediate Delta
-		if bounds.lenY-bounds.y > 0 {
-			immediate.Added = []Mark{Mark{bounds.y, bounds.lenY}}
-		}
-		if bounds.lenX-bounds.x > 0 {
-			immediate.Removed = []Mark{Mark{bounds.x, bounds.lenX}}
-		}
-		return immediate
+		return unmatched(bounds)
 	}
 
-	var left Delta = mx.recursiveDiff(box{point{bounds.x, bounds.y}, m.x, m.y})
-	var right Delta = mx.recursiveDiff(box{point{m.x + m.length, m.y + m.length}, bounds.lenX, bounds.lenY})
+	var left Delta = mx.recursiveDiff(box{point{bounds.x, bounds.y}, m.x, m.y}, depth+1)
+	if mx.collect {
+		mx.common = append(mx.common, m)
+	}
+	var right Delta = mx.recursiveDiff(box{point{m.x + m.length, m.y + m.length}, bounds.lenX, bounds.lenY}, depth+1)
 
+	// The left box ends where the match starts and the right one begins where
+	// it ends, in both sequences, so left's marks precede right's on either side
+	// regardless of whether the match lies above or below the main diagonal
 	var result Delta
 
 	result.Added = append(left.Added, right.Added...)
@@ -95,52 +345,118 @@ func (mx *matrix) recursiveDiff(bounds box) Delta {
 	return result
 }
 
+// Returns a delta that removes and adds the whole bounds
+func unmatched(bounds box) Delta {
+	var immediate Delta
+	if bounds.lenY-bounds.y > 0 {
+		immediate.Added = []Mark{Mark{bounds.y, bounds.lenY}}
+	}
+	if bounds.lenX-bounds.x > 0 {
+		immediate.Removed = []Mark{Mark{bounds.x, bounds.lenX}}
+	}
+	return immediate
+}
+
 // Finds the largest common substring by looking at the provided match matrix
 // starting from (bounds.x, bounds.y) with lengths bounds.lenX, bounds.lenY
 func (mx *matrix) largest(bounds box) match {
 	var result match
 
 	// Look for LCS in the too-right half, including the main diagonal
-	for i := bounds.x; i < bounds.lenX && result.length < (bounds.lenX-i); i++ {
-		var m match = mx.search(box{point{i, bounds.y}, bounds.lenX, bounds.lenY})
-		if m.length > result.length {
+	for i := bounds.x; i < mx.limit(bounds.x, bounds.lenX) && mx.fits(result, bounds.lenX-i) && !mx.stopped(); i++ {
+		var m match = mx.search(point{i, bounds.y}, bounds)
+		if mx.better(m, result, bounds) {
 			result = m
 		}
 	}
 
 	// Look for LCS in the bottom-left half, excluding the main diagonal
-	for j := bounds.y + 1; j < bounds.lenY && result.length < (bounds.lenY-j); j++ {
-		var m match = mx.search(box{point{bounds.x, j}, bounds.lenX, bounds.lenY})
-		if m.length > result.length {
+	for j := bounds.y + 1; j < mx.limit(bounds.y, bounds.lenY) && mx.fits(result, bounds.lenY-j) && !mx.stopped(); j++ {
+		var m match = mx.search(point{bounds.x, j}, bounds)
+		if mx.better(m, result, bounds) {
 			result = m
 		}
 	}
 	return result
 }
 
-// Searches the main diagonal for the longest sequential match line
-func (mx *matrix) search(bounds box) (result match) {
+// Returns the end of the diagonals' starting positions to search between from and to
+func (mx *matrix) limit(from, to int) int {
+	if mx.window > 0 && from+mx.window+1 < to {
+		return from + mx.window + 1
+	}
+	return to
+}
+
+// Reports whether a diagonal of the given length can hold a better match than the result
+func (mx *matrix) fits(result match, length int) bool {
+	if mx.score != nil { // Shorter runs may score higher
+		return true
+	}
+	if mx.pivot == Balanced {
+		return result.length <= length
+	}
+	return result.length < length
+}
+
+// Reports whether m is a better pivot for the bounds than the current result
+func (mx *matrix) better(m, result match, bounds box) bool {
+	if m.length < mx.minMatch || (mx.maxDrift > 0 && abs(m.x-m.y) > mx.maxDrift) {
+		return false
+	}
+	if mx.score != nil && m.length > 0 && result.length > 0 {
+		var removed, added Mark = Mark{bounds.x, bounds.lenX}, Mark{bounds.y, bounds.lenY}
+		return mx.score(m.x, m.y, m.length, removed, added) > mx.score(result.x, result.y, result.length, removed, added)
+	}
+	if mx.pivot != Balanced || m.length != result.length {
+		return m.length > result.length
+	}
+	return m.length > 0 && skew(m, bounds) < skew(result, bounds)
+}
+
+// Returns how unevenly the match splits the bounds as the difference
+// between the sizes of the boxes that precede and follow it
+func skew(m match, bounds box) int {
+	return abs((m.x-bounds.x)-(bounds.lenX-m.x-m.length)) + abs((m.y-bounds.y)-(bounds.lenY-m.y-m.length))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Searches the diagonal starting at the given point for the longest sequential match line
+func (mx *matrix) search(from point, bounds box) (result match) {
 	var inMatch bool
 	var m match
-	for step := 0; step+bounds.x < bounds.lenX && step+bounds.y < bounds.lenY; {
-		var current point = point{step + bounds.x, step + bounds.y}
-		if length, found := mx.matches[current]; found {
-			if length > result.length {
+	for step := 0; step+from.x < bounds.lenX && step+from.y < bounds.lenY; {
+		var current point = point{step + from.x, step + from.y}
+		// Only positive lengths are cached, anything else would stall the
+		// step and is treated as a cache miss so that the run is recomputed
+		if length, found := mx.matches[current]; found && length > 0 {
+			// The run may have been cached while searching an enclosing box
+			// and extend past this one, so it is clipped to the bounds
+			length = min(length, min(bounds.lenX-current.x, bounds.lenY-current.y))
+			if mx.better(match{current, length}, result, bounds) {
 				result.point = current
 				result.length = length
 			}
 			step += length
 			continue
 		}
-		if mx.v.Peek(mx.at(current)) {
+		if mx.equal(current) {
 			if !inMatch { // Create a new current record if there is none ...
 				inMatch, m.point, m.length = true, current, 1
 			} else { // ... otherwise just increment the existing
 				m.length++
 			}
 			// Update the length in the cache
-			mx.matches[m.point] = m.length
-			if m.length > result.length {
+			if mx.matches != nil {
+				mx.matches[m.point] = m.length
+			}
+			if mx.better(m, result, bounds) {
 				result = m // Store it if it is longer ...
 			}
 		} else { // End of current of match
@@ -172,3 +488,15 @@ func (d impl) Equal(i, j int) bool {
 func WithEqual(len1 int, len2 int, equal func(int, int) bool) Interface {
 	return impl{len1: len1, len2: len2, equal: equal}
 }
+
+// Returns a diff.Interface implementation for the specified lengths and equal
+// function in which wildcard elements, as reported by isWild for the first
+// (side 0) or the second (side 1) sequence, are equal to any element.
+// This aligns templates with holes against concrete sequences. Note that
+// equality is no longer transitive, so a wildcard matches elements
+// that are not equal to each other.
+func WithWildcard(len1, len2 int, equal func(i, j int) bool, isWild func(side, idx int) bool) Interface {
+	return impl{len1: len1, len2: len2, equal: func(i, j int) bool {
+		return isWild(0, i) || isWild(1, j) || equal(i, j)
+	}}
+}
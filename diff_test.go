@@ -2,6 +2,7 @@ package diff // import "github.com/spaskalev/diff"
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -36,3 +37,368 @@ func Test(t *testing.T) {
 		}
 	}
 }
+
+func TestPivot(t *testing.T) {
+	// Every element of seq1 is matched by a run of length one in seq2,
+	// so the largest pivot peels off a single run per recursion level
+	var seq1, seq2 string = "abcdefghijklmnop", "axbxcxdxexfxgxhxixjxkxlxmxnxoxpx"
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	largest, largestStats := Config{Pivot: Largest}.DiffStats(data)
+	balanced, balancedStats := Config{Pivot: Balanced}.DiffStats(data)
+
+	if fmt.Sprintf("%v", largest) != fmt.Sprintf("%v", Diff(data)) {
+		t.Errorf("Expected the largest pivot to be the default, got %v", largest)
+	}
+	if fmt.Sprintf("%v", balanced) != fmt.Sprintf("%v", largest) {
+		t.Errorf("Expected the same delta for both pivots, got\n%v\n%v", balanced, largest)
+	}
+	if largestStats.Depth != len(seq1)+1 {
+		t.Errorf("Unexpected recursion depth for the largest pivot: %d", largestStats.Depth)
+	}
+	if balancedStats.Depth > 6 {
+		t.Errorf("Unexpected recursion depth for the balanced pivot: %d", balancedStats.Depth)
+	}
+}
+
+func TestDiffWindowed(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		window     int
+		delta      Delta
+	}{
+		{"abcdef", "abcdef", 1, Delta{}},
+		// The shift accumulates over the sequence but each step is local
+		{"abcdefgh", "XabYcdZefWgh", 1, Delta{Added: []Mark{Mark{0, 1}, Mark{3, 4}, Mark{6, 7}, Mark{9, 10}}}},
+		// The match is too far from the start of the region
+		{"abcd", "XYZabcd", 2, Delta{Added: []Mark{Mark{0, 7}}, Removed: []Mark{Mark{0, 4}}}},
+		{"abcd", "XYZabcd", 3, Delta{Added: []Mark{Mark{0, 3}}}},
+		{"abcd", "XYZabcd", 0, Delta{Added: []Mark{Mark{0, 3}}}},
+	}
+
+	for _, testCase := range data {
+		delta := DiffWindowed(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}), testCase.window)
+
+		if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected delta for data\n[%s]\n[%s]\nwithin %d\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, testCase.window, delta, testCase.delta)
+		}
+	}
+}
+
+func TestSplitScore(t *testing.T) {
+	var seq1, seq2 string = "abcdefghijklmnop", "axbxcxdxexfxgxhxixjxkxlxmxnxoxpx"
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	var longest Delta = Config{Score: func(x, y, length int, removed, added Mark) int {
+		return length
+	}}.Diff(data)
+	if fmt.Sprintf("%v", longest) != fmt.Sprintf("%v", Diff(data)) {
+		t.Errorf("Expected a length score to match the default, got %v", longest)
+	}
+
+	centered, stats := Config{Score: Centered}.DiffStats(data)
+	if err := Verify(data, centered, nil); err != nil {
+		t.Errorf("Unexpected invalid delta %v: %v", centered, err)
+	}
+	if stats.Depth > 6 {
+		t.Errorf("Unexpected recursion depth for a centered score: %d", stats.Depth)
+	}
+
+	if score := Centered(4, 4, 4, Mark{0, 12}, Mark{0, 12}); score <= Centered(0, 0, 4, Mark{0, 12}, Mark{0, 12}) {
+		t.Errorf("Expected a centered run to outscore one at the edge")
+	}
+	if score := Centered(5, 5, 2, Mark{0, 12}, Mark{0, 12}); score >= Centered(0, 0, 4, Mark{0, 12}, Mark{0, 12}) {
+		t.Errorf("Expected a much longer run to outscore a centered one")
+	}
+}
+
+func TestMarksOrder(t *testing.T) {
+	// The largest match of each pair lies below the main diagonal,
+	// i.e. it starts further into the second sequence than the first
+	data := []struct {
+		seq1, seq2 string
+		delta      Delta
+	}{
+		{"abcd", "xyzabcdq", Delta{Added: []Mark{Mark{0, 3}, Mark{7, 8}}}},
+		{"pabcdq", "xyzwabcdpq", Delta{
+			Added:   []Mark{Mark{0, 4}, Mark{8, 9}},
+			Removed: []Mark{Mark{0, 1}},
+		}},
+		{"qrabcdst", "stuvwxyzabcdqr", Delta{
+			Added:   []Mark{Mark{0, 8}, Mark{12, 14}},
+			Removed: []Mark{Mark{0, 2}, Mark{6, 8}},
+		}},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		delta := Diff(input)
+
+		if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected delta for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, delta, testCase.delta)
+		}
+		if err := Verify(input, delta, nil); err != nil {
+			t.Errorf("Expected ordered and valid marks for data\n[%s]\n[%s]\nGot %v",
+				testCase.seq1, testCase.seq2, err)
+		}
+	}
+}
+
+func TestDiffMatchesConfig(t *testing.T) {
+	// Transposed elements, which the positional scan would replace
+	data := []struct {
+		seq1, seq2 string
+	}{
+		{"abcdefgh", "abdcefgh"},
+		{"abcdefgh", "bacdefgh"},
+		{"abcdefgh", "abcdefhg"},
+		{"abcdefghijklmnop", "abcedfghijlkmnop"},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		if delta, expected := Diff(input), (Config{}).Diff(input); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+			t.Errorf("Unexpected delta for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, delta, expected)
+		}
+		var minimal Delta = myers(input, box{point{0, 0}, len(testCase.seq1), len(testCase.seq2)})
+		if removed, added := Diff(input).Len(); removed+added != count(minimal.Removed)+count(minimal.Added) {
+			t.Errorf("Unexpected non-minimal delta for data\n[%s]\n[%s]\nGot %v", testCase.seq1, testCase.seq2, Diff(input))
+		}
+	}
+}
+
+func TestPositional(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		delta      Delta
+	}{
+		{"abcdefgh", "abcdefgh", Delta{}},
+		{"abcdefgh", "abcXefgh", Delta{Added: []Mark{Mark{3, 4}}, Removed: []Mark{Mark{3, 4}}}},
+		{"abcdefgh", "Xbcdefgh", Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{0, 1}}}},
+		{"abcdefgh", "abcdefgX", Delta{Added: []Mark{Mark{7, 8}}, Removed: []Mark{Mark{7, 8}}}},
+		{"abcdefghijklmnop", "aXcdefYZijklmnoW", Delta{
+			Added:   []Mark{Mark{1, 2}, Mark{6, 8}, Mark{15, 16}},
+			Removed: []Mark{Mark{1, 2}, Mark{6, 8}, Mark{15, 16}},
+		}},
+		// Changed element that reappears elsewhere
+		{"abcabc", "abXabc", Delta{Added: []Mark{Mark{2, 3}}, Removed: []Mark{Mark{2, 3}}}},
+	}
+
+	for _, testCase := range data {
+		var calls int
+		delta := Config{Positional: true}.Diff(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			calls++
+			return testCase.seq1[i] == testCase.seq2[j]
+		}))
+
+		if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected delta for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, delta, testCase.delta)
+		}
+		if calls != len(testCase.seq1) {
+			t.Errorf("Expected a linear scan for data\n[%s]\n[%s]\nGot %d comparisons",
+				testCase.seq1, testCase.seq2, calls)
+		}
+	}
+}
+
+func TestSearchCache(t *testing.T) {
+	var seq1, seq2 string = "abcd", "abcd"
+	var mx *matrix = Config{}.matrix(len(seq1), len(seq2))
+	mx.fill(WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	}), 0, len(seq1))
+
+	// A zero-length entry must not stall the search
+	mx.matches[point{0, 0}] = 0
+	if m := mx.search(point{0, 0}, box{point{0, 0}, 4, 4}); m != (match{point{0, 0}, 4}) {
+		t.Errorf("Unexpected match %v", m)
+	}
+
+	// A run cached for an enclosing box is clipped to the current one
+	if m := mx.search(point{0, 0}, box{point{0, 0}, 2, 3}); m != (match{point{0, 0}, 2}) {
+		t.Errorf("Unexpected match %v", m)
+	}
+
+	// Runs cached in the first split used to overflow the left box
+	var delta Delta = Diff(WithEqual(4, 7, func(i, j int) bool {
+		return "cabc"[i] == "caaabcc"[j]
+	}))
+	if fmt.Sprintf("%v", delta) != "{[{1 3} {6 7}] []}" {
+		t.Errorf("Unexpected delta %v", delta)
+	}
+}
+
+func TestSmallSequences(t *testing.T) {
+	data := []struct {
+		seq1  string
+		seq2  string
+		delta Delta
+	}{
+		// 1x1
+		{"a", "a", Delta{}},
+		{"a", "b", Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{0, 1}}}},
+		// 1xN
+		{"a", "ab", Delta{Added: []Mark{Mark{1, 2}}}},
+		{"a", "ba", Delta{Added: []Mark{Mark{0, 1}}}},
+		{"a", "xax", Delta{Added: []Mark{Mark{0, 1}, Mark{2, 3}}}},
+		{"a", "aaa", Delta{Added: []Mark{Mark{1, 3}}}},
+		{"a", "xyz", Delta{Added: []Mark{Mark{0, 3}}, Removed: []Mark{Mark{0, 1}}}},
+		// Nx1
+		{"ab", "a", Delta{Removed: []Mark{Mark{1, 2}}}},
+		{"ba", "a", Delta{Removed: []Mark{Mark{0, 1}}}},
+		{"xax", "a", Delta{Removed: []Mark{Mark{0, 1}, Mark{2, 3}}}},
+		{"aaa", "a", Delta{Removed: []Mark{Mark{1, 3}}}},
+		{"xyz", "a", Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{0, 3}}}},
+	}
+
+	var configs []Config = []Config{{}, {Pivot: Balanced}, {Score: Centered}, {Window: 1}, {CacheSize: 1}}
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		if delta := Diff(input); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected delta for [%s] [%s]\nGot %v\nExpected %v", testCase.seq1, testCase.seq2, delta, testCase.delta)
+		}
+		// Other configurations may place the marks differently, e.g. around a centered match
+		for _, config := range configs {
+			var delta Delta = config.Diff(input)
+			if err := Verify(input, delta, nil); err != nil {
+				t.Errorf("Unexpected invalid delta %v for [%s] [%s] with %+v: %v", delta, testCase.seq1, testCase.seq2, config, err)
+			}
+			if fmt.Sprint(delta.Len()) != fmt.Sprint(testCase.delta.Len()) {
+				t.Errorf("Unexpected delta size for [%s] [%s] with %+v\nGot %v\nExpected %v", testCase.seq1, testCase.seq2, config, delta, testCase.delta)
+			}
+		}
+	}
+}
+
+func TestWithWildcard(t *testing.T) {
+	data := []struct {
+		template, concrete string
+		delta              Delta
+	}{
+		{"ab?d", "abcd", Delta{}},
+		{"ab?d", "abxd", Delta{}},
+		{"a??", "xyz", Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{0, 1}}}},
+		{"a?c", "abbc", Delta{Added: []Mark{Mark{2, 3}}}},
+		{"abc", "a?c", Delta{}},
+	}
+
+	for _, testCase := range data {
+		var template, concrete string = testCase.template, testCase.concrete
+		var input Interface = WithWildcard(len(template), len(concrete), func(i, j int) bool {
+			return template[i] == concrete[j]
+		}, func(side, idx int) bool {
+			return (side == 0 && template[idx] == '?') || (side == 1 && concrete[idx] == '?')
+		})
+		if delta := Diff(input); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected delta for [%s] [%s]\nGot %v\nExpected %v", template, concrete, delta, testCase.delta)
+		}
+	}
+}
+
+func TestDiffMinMatch(t *testing.T) {
+	data := []struct {
+		seq1     string
+		seq2     string
+		minMatch int
+		delta    Delta
+	}{
+		{"ab cd", "ef gh", 0, Delta{Added: []Mark{Mark{0, 2}, Mark{3, 5}}, Removed: []Mark{Mark{0, 2}, Mark{3, 5}}}},
+		{"ab cd", "ef gh", 2, Delta{Added: []Mark{Mark{0, 5}}, Removed: []Mark{Mark{0, 5}}}},
+		{"abc def", "xbc yef", 2, Delta{Added: []Mark{Mark{0, 1}, Mark{4, 5}}, Removed: []Mark{Mark{0, 1}, Mark{4, 5}}}},
+		{"abc def", "xbc yef", 3, Delta{Added: []Mark{Mark{0, 1}, Mark{4, 7}}, Removed: []Mark{Mark{0, 1}, Mark{4, 7}}}},
+		{"abc def", "xbc yef", 4, Delta{Added: []Mark{Mark{0, 7}}, Removed: []Mark{Mark{0, 7}}}},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		if delta := DiffMinMatch(input, testCase.minMatch); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected delta for [%s] [%s] with %d\nGot %v\nExpected %v", testCase.seq1, testCase.seq2, testCase.minMatch, delta, testCase.delta)
+		}
+	}
+}
+
+func TestDiffPrefix(t *testing.T) {
+	// Long sequences that differ early and late
+	var seq1, seq2 []int = make([]int, 1000), make([]int, 1200)
+	for i := range seq1 {
+		seq1[i] = i
+	}
+	for j := range seq2 {
+		seq2[j] = j
+	}
+	seq1[10], seq2[900] = -1, -1
+
+	var input Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		if i >= 100 || j >= 100 {
+			t.Fatalf("Unexpected comparison past the prefix at %d, %d", i, j)
+		}
+		return seq1[i] == seq2[j]
+	})
+	var delta Delta = DiffPrefix(input, 100)
+	var expected Delta = Delta{Added: []Mark{Mark{10, 11}}, Removed: []Mark{Mark{10, 11}}}
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+	for _, marks := range [][]Mark{delta.Added, delta.Removed} {
+		for _, m := range marks {
+			if m.Length > 100 {
+				t.Errorf("Unexpected mark %v past the prefix", m)
+			}
+		}
+	}
+
+	if delta := DiffPrefix(input, 0); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", Delta{}) {
+		t.Errorf("Unexpected delta for an empty prefix\nGot %v", delta)
+	}
+}
+
+func TestAnchorTail(t *testing.T) {
+	// The longest common run pulls the tail of the first sequence
+	// into the middle of the second one
+	var seq1, seq2 string = "abcdefXYZ", "defXYZQQQQXYZ"
+	var input Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	var unanchored Delta = Delta{Added: []Mark{Mark{6, 13}}, Removed: []Mark{Mark{0, 3}}}
+	if delta := Diff(input); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", unanchored) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, unanchored)
+	}
+
+	var anchored Delta = Delta{Added: []Mark{Mark{3, 10}}, Removed: []Mark{Mark{0, 3}}}
+	var delta Delta = Config{AnchorTail: true}.Diff(input)
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", anchored) {
+		t.Errorf("Unexpected anchored delta\nGot %v\nExpected %v", delta, anchored)
+	}
+	if err := Verify(input, delta, nil); err != nil {
+		t.Errorf("Unexpected invalid delta %v: %v", delta, err)
+	}
+
+	// A large change in the middle leaves the tail matched
+	seq1, seq2 = "head"+strings.Repeat("a", 50)+"tail", "head"+strings.Repeat("b", 80)+"tail"
+	input = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+	delta = Config{AnchorTail: true}.Diff(input)
+	if delta.Added[len(delta.Added)-1].Length > len(seq2)-4 || delta.Removed[len(delta.Removed)-1].Length > len(seq1)-4 {
+		t.Errorf("Unexpected change in the tail %v", delta)
+	}
+}
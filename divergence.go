@@ -0,0 +1,36 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Returns up to n of the earliest positions (i, j) where the sequences
+// stop being aligned, without computing a full diff. The sequences are
+// scanned forward in lockstep and after each divergence the scan resumes
+// at the nearest pair of equal elements, i.e. the one that skips the fewest
+// elements of both sequences combined. A sequence ending before the other
+// counts as a divergence at its end.
+func Divergences(data Interface, n int) [][2]int {
+	var len1, len2 = data.Len()
+	var result [][2]int
+	var i, j int
+	for len(result) < n && (i < len1 || j < len2) {
+		if i < len1 && j < len2 && data.Equal(i, j) {
+			i, j = i+1, j+1
+			continue
+		}
+		result = append(result, [2]int{i, j})
+		i, j = resync(data, i, j, len1, len2)
+	}
+	return result
+}
+
+// Returns the nearest pair of equal elements following (i, j),
+// or the ends of the sequences if there is none
+func resync(data Interface, i, j, len1, len2 int) (int, int) {
+	for distance := 1; distance <= (len1-i)+(len2-j); distance++ {
+		for di := 0; di <= distance; di++ {
+			var x, y int = i + di, j + distance - di
+			if x < len1 && y < len2 && data.Equal(x, y) {
+				return x, y
+			}
+		}
+	}
+	return len1, len2
+}
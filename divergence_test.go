@@ -0,0 +1,33 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDivergences(t *testing.T) {
+	data := []struct {
+		seq1, seq2  string
+		n           int
+		divergences [][2]int
+	}{
+		{"abc", "abc", 5, nil},
+		{"abcdef", "abXdef", 5, [][2]int{{2, 2}}},
+		{"abcdef", "abcdefgh", 5, [][2]int{{6, 6}}},
+		{"abcdefghij", "aXcdeYghiZj", 5, [][2]int{{1, 1}, {5, 5}, {9, 9}}},
+		{"abcdefghij", "aXcdeYghiZj", 2, [][2]int{{1, 1}, {5, 5}}},
+		{"abcdefghij", "abdefgXij", 5, [][2]int{{2, 2}, {7, 6}}},
+		{"abc", "xyz", 5, [][2]int{{0, 0}}},
+	}
+
+	for _, testCase := range data {
+		var divergences [][2]int = Divergences(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}), testCase.n)
+
+		if fmt.Sprint(divergences) != fmt.Sprint(testCase.divergences) {
+			t.Errorf("Unexpected divergences for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, divergences, testCase.divergences)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"math"
+)
+
+// Aligns two time series with dynamic time warping, which unlike a diff
+// matches every sample of one series to one or more samples of the other,
+// so that series differing in speed are aligned. Returns the warping path
+// as pairs of indices from (0, 0) to (len(a)-1, len(b)-1) and its total
+// cost as the sum of the absolute differences of the paired samples.
+// When window is positive the path is constrained to the Sakoe-Chiba band
+// |i - j| <= window, widened to the difference of the lengths if needed,
+// which limits the cost computations and the memory they take
+// to O(len(a) * window) cells.
+// Empty series have an empty path of zero cost.
+func DiffDTW(a, b []float64, window int) (path [][2]int, cost float64) {
+	var n, m int = len(a), len(b)
+	if n == 0 || m == 0 {
+		return nil, 0
+	}
+	if window <= 0 {
+		window = max(n, m)
+	}
+	window = max(window, abs(n-m))
+
+	// The cumulative cost of the best path to each cell within the band,
+	// row by row, with each row holding just its part of the band
+	var costs [][]float64 = make([][]float64, n)
+	var at = func(i, j int) float64 {
+		if from := max(0, i-window); j >= from && j-from < len(costs[i]) {
+			return costs[i][j-from]
+		}
+		return math.Inf(1)
+	}
+	for i := range costs {
+		var from int = max(0, i-window)
+		costs[i] = make([]float64, min(m, i+window+1)-from)
+		for j := from; j < from+len(costs[i]); j++ {
+			var previous float64
+			switch {
+			case i == 0 && j == 0:
+				previous = 0
+			case i == 0:
+				previous = at(i, j-1)
+			case j == 0:
+				previous = at(i-1, j)
+			default:
+				previous = math.Min(at(i-1, j-1), math.Min(at(i-1, j), at(i, j-1)))
+			}
+			costs[i][j-from] = previous + math.Abs(a[i]-b[j])
+		}
+	}
+
+	// Walk back from the end along the cheapest predecessors
+	var i, j int = n - 1, m - 1
+	for {
+		path = append(path, [2]int{i, j})
+		if i == 0 && j == 0 {
+			break
+		}
+		switch {
+		case i == 0:
+			j--
+		case j == 0:
+			i--
+		case at(i-1, j-1) <= at(i-1, j) && at(i-1, j-1) <= at(i, j-1):
+			i, j = i-1, j-1
+		case at(i-1, j) <= at(i, j-1):
+			i--
+		default:
+			j--
+		}
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return path, at(n-1, m-1)
+}
@@ -0,0 +1,50 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiffDTW(t *testing.T) {
+	// The second series is the first one played at half the speed
+	var a, b []float64
+	for i := 0; i < 20; i++ {
+		a = append(a, math.Sin(float64(i)/3))
+		b = append(b, a[i], a[i])
+	}
+
+	for _, window := range []int{0, 3, 25} {
+		path, cost := DiffDTW(a, b, window)
+		if cost != 0 {
+			t.Errorf("Expected a zero cost alignment with window %d, got %f", window, cost)
+		}
+		if len(path) != len(b) || path[0] != [2]int{0, 0} || path[len(path)-1] != [2]int{len(a) - 1, len(b) - 1} {
+			t.Fatalf("Unexpected path with window %d: %v", window, path)
+		}
+		for k, p := range path {
+			if p[0] != p[1]/2 {
+				t.Errorf("Unexpected pair %v at step %d with window %d", p, k, window)
+			}
+		}
+	}
+
+	path, cost := DiffDTW([]float64{0, 1, 2}, []float64{0, 2}, 0)
+	if cost != 1 || len(path) != 3 {
+		t.Errorf("Unexpected alignment %v with cost %f", path, cost)
+	}
+	if path, cost := DiffDTW(nil, []float64{1}, 0); path != nil || cost != 0 {
+		t.Errorf("Expected an empty alignment for an empty series, got %v %f", path, cost)
+	}
+}
+
+func TestDiffDTWBand(t *testing.T) {
+	// Far too long for a full matrix, only the band is stored
+	var a []float64 = make([]float64, 100000)
+	for i := range a {
+		a[i] = float64(i % 7)
+	}
+	path, cost := DiffDTW(a, a, 2)
+	if cost != 0 || len(path) != len(a) {
+		t.Errorf("Unexpected alignment of length %d with cost %f", len(path), cost)
+	}
+}
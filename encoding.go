@@ -0,0 +1,166 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+)
+
+// ErrEncoding is returned when decoding a malformed delta
+var ErrEncoding = errors.New("diff: invalid delta encoding")
+
+// Encodes the delta for encoding/gob in a compact form: the number of removed
+// and added marks followed by each mark as the gap that precedes it and
+// its length, all as variable-length integers. This is the form whose size
+// EstimateSize(0) reports. The marks must be ordered and non-overlapping.
+//
+// As the method is promoted to the types that embed a Delta, each of these
+// encodes itself in turn, lest gob drop all but their Delta.
+func (d Delta) GobEncode() ([]byte, error) {
+	var result []byte = make([]byte, 0, d.EstimateSize(0))
+	result = binary.AppendUvarint(result, uint64(len(d.Removed)))
+	result = binary.AppendUvarint(result, uint64(len(d.Added)))
+	for side, marks := range [][]Mark{d.Removed, d.Added} {
+		var end int
+		for _, m := range marks {
+			if m.From < end || m.Length < m.From {
+				return nil, &MarkError{Side: side, Mark: m}
+			}
+			result = binary.AppendUvarint(result, uint64(m.From-end))
+			result = binary.AppendUvarint(result, uint64(m.Length-m.From))
+			end = m.Length
+		}
+	}
+	return result, nil
+}
+
+// Decodes a delta encoded by GobEncode. As with gob's own encoding
+// of slices, no marks decode as a nil slice, whether the encoded
+// delta held a nil or an empty one.
+func (d *Delta) GobDecode(data []byte) error {
+	var read = func() (int, error) {
+		value, n := binary.Uvarint(data)
+		if n <= 0 || value > uint64(maxInt) {
+			return 0, ErrEncoding
+		}
+		data = data[n:]
+		return int(value), nil
+	}
+
+	var counts [2]int
+	for k := range counts {
+		var err error
+		if counts[k], err = read(); err != nil {
+			return err
+		}
+	}
+	// Each mark takes at least two bytes
+	if counts[0] > len(data)/2 || counts[1] > len(data)/2-counts[0] {
+		return ErrEncoding
+	}
+
+	var result [2][]Mark
+	for k, count := range counts {
+		var end int
+		for n := 0; n < count; n++ {
+			gap, err := read()
+			if err != nil {
+				return err
+			}
+			length, err := read()
+			if err != nil {
+				return err
+			}
+			if gap > maxInt-end || length > maxInt-end-gap {
+				return ErrEncoding
+			}
+			result[k] = append(result[k], Mark{end + gap, end + gap + length})
+			end += gap + length
+		}
+	}
+	if len(data) > 0 {
+		return ErrEncoding
+	}
+	*d = Delta{Removed: result[0], Added: result[1]}
+	return nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// The gob forms of the types that embed a Delta, which hold it
+// in a named field instead so that only it is encoded by Delta
+type (
+	treeGob struct {
+		Delta  Delta
+		Nested []Nested
+	}
+	keyedChangesGob struct {
+		Delta               Delta
+		Unchanged, Modified [][2]int
+	}
+	sectionGob struct {
+		Header string
+		Line   int
+		Delta  Delta
+	}
+)
+
+// Encodes the value with a gob encoder of its own
+func encodeGob(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decodes a value encoded by encodeGob
+func decodeGob(data []byte, value any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
+
+// Encodes the tree for encoding/gob along with its nested trees
+func (t Tree) GobEncode() ([]byte, error) {
+	return encodeGob(treeGob{t.Delta, t.Nested})
+}
+
+// Decodes a tree encoded by GobEncode
+func (t *Tree) GobDecode(data []byte) error {
+	var result treeGob
+	if err := decodeGob(data, &result); err != nil {
+		return err
+	}
+	*t = Tree{result.Delta, result.Nested}
+	return nil
+}
+
+// Encodes the changes for encoding/gob along with the kept elements
+func (k KeyedChanges) GobEncode() ([]byte, error) {
+	return encodeGob(keyedChangesGob{k.Delta, k.Unchanged, k.Modified})
+}
+
+// Decodes changes encoded by GobEncode
+func (k *KeyedChanges) GobDecode(data []byte) error {
+	var result keyedChangesGob
+	if err := decodeGob(data, &result); err != nil {
+		return err
+	}
+	*k = KeyedChanges{result.Delta, result.Unchanged, result.Modified}
+	return nil
+}
+
+// Encodes the section for encoding/gob along with its header
+func (s Section) GobEncode() ([]byte, error) {
+	return encodeGob(sectionGob{s.Header, s.Line, s.Delta})
+}
+
+// Decodes a section encoded by GobEncode
+func (s *Section) GobDecode(data []byte) error {
+	var result sectionGob
+	if err := decodeGob(data, &result); err != nil {
+		return err
+	}
+	*s = Section{result.Header, result.Line, result.Delta}
+	return nil
+}
@@ -0,0 +1,89 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+)
+
+func TestGob(t *testing.T) {
+	data := []struct {
+		delta    Delta
+		expected Delta
+	}{
+		{Delta{}, Delta{}},
+		{Delta{Added: []Mark{}, Removed: []Mark{}}, Delta{}},
+		{Delta{Added: []Mark{Mark{2, 3}}}, Delta{Added: []Mark{Mark{2, 3}}}},
+		{
+			Delta{Added: []Mark{Mark{0, 1}, Mark{5, 300}}, Removed: []Mark{Mark{3, 4}, Mark{1000, 1002}}},
+			Delta{Added: []Mark{Mark{0, 1}, Mark{5, 300}}, Removed: []Mark{Mark{3, 4}, Mark{1000, 1002}}},
+		},
+	}
+
+	for _, testCase := range data {
+		var buffer bytes.Buffer
+		if err := gob.NewEncoder(&buffer).Encode(testCase.delta); err != nil {
+			t.Fatalf("Unexpected error encoding %v: %v", testCase.delta, err)
+		}
+		var decoded Delta
+		if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+			t.Fatalf("Unexpected error decoding %v: %v", testCase.delta, err)
+		}
+		if fmt.Sprintf("%#v", decoded) != fmt.Sprintf("%#v", testCase.expected) {
+			t.Errorf("Unexpected round trip of %#v\nGot %#v\nExpected %#v", testCase.delta, decoded, testCase.expected)
+		}
+
+		encoded, _ := testCase.delta.GobEncode()
+		if len(encoded) != testCase.delta.EstimateSize(0) {
+			t.Errorf("Unexpected size of %v\nGot %d\nExpected %d", testCase.delta, len(encoded), testCase.delta.EstimateSize(0))
+		}
+	}
+
+	if _, err := (Delta{Added: []Mark{Mark{5, 6}, Mark{2, 3}}}).GobEncode(); err == nil {
+		t.Errorf("Expected an error for unordered marks")
+	}
+	for _, malformed := range [][]byte{nil, {1}, {1, 0, 2}, {1, 0, 2, 3, 4}, {0xff, 0xff, 0xff, 0xff, 0x0f, 0}} {
+		var decoded Delta
+		if err := decoded.GobDecode(malformed); err != ErrEncoding {
+			t.Errorf("Expected an encoding error for %v, got %v", malformed, err)
+		}
+	}
+}
+
+// Round-trips the value through encoding/gob into the result
+func roundTrip(t *testing.T, value, result any) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		t.Fatalf("Unexpected error encoding %v: %v", value, err)
+	}
+	if err := gob.NewDecoder(&buffer).Decode(result); err != nil {
+		t.Fatalf("Unexpected error decoding %v: %v", value, err)
+	}
+}
+
+func TestGobEmbedded(t *testing.T) {
+	var delta Delta = Delta{Added: []Mark{Mark{1, 2}}, Removed: []Mark{Mark{1, 3}}}
+
+	var tree Tree = Tree{delta, []Nested{{1, 1, &Tree{Delta: Delta{Added: []Mark{Mark{0, 4}}}}}}}
+	var decodedTree Tree
+	roundTrip(t, tree, &decodedTree)
+	if len(decodedTree.Nested) != 1 || fmt.Sprint(decodedTree.Delta, decodedTree.Nested[0].X, decodedTree.Nested[0].Y, *decodedTree.Nested[0].Tree) !=
+		fmt.Sprint(tree.Delta, tree.Nested[0].X, tree.Nested[0].Y, *tree.Nested[0].Tree) {
+		t.Errorf("Unexpected round trip of %v\nGot %v", tree, decodedTree)
+	}
+
+	var changes KeyedChanges = KeyedChanges{delta, [][2]int{{0, 0}}, [][2]int{{3, 2}}}
+	var decodedChanges KeyedChanges
+	roundTrip(t, changes, &decodedChanges)
+	if fmt.Sprintf("%#v", decodedChanges) != fmt.Sprintf("%#v", changes) {
+		t.Errorf("Unexpected round trip of %#v\nGot %#v", changes, decodedChanges)
+	}
+
+	var section Section = Section{"hdr", 4, delta}
+	var decodedSection Section
+	roundTrip(t, section, &decodedSection)
+	if fmt.Sprintf("%#v", decodedSection) != fmt.Sprintf("%#v", section) {
+		t.Errorf("Unexpected round trip of %#v\nGot %#v", section, decodedSection)
+	}
+}
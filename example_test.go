@@ -0,0 +1,85 @@
+package diff_test
+
+import (
+	"fmt"
+
+	"github.com/spaskalev/diff"
+)
+
+// A rope of text split into chunks, with O(log n) access by index
+type rope struct {
+	left, right *rope
+	length      int
+	leaf        string
+}
+
+func newRope(chunks ...string) *rope {
+	if len(chunks) == 1 {
+		return &rope{length: len(chunks[0]), leaf: chunks[0]}
+	}
+	var left, right *rope = newRope(chunks[:len(chunks)/2]...), newRope(chunks[len(chunks)/2:]...)
+	return &rope{left: left, right: right, length: left.length + right.length}
+}
+
+func (r *rope) index(i int) byte {
+	for r.left != nil {
+		if i < r.left.length {
+			r = r.left
+		} else {
+			i, r = i-r.left.length, r.right
+		}
+	}
+	return r.leaf[i]
+}
+
+// A diff.Interface over two ropes. Equal is called for each index of the
+// second rope before the first index advances, so the current byte of the
+// first rope is cached to avoid a lookup per comparison.
+type ropes struct {
+	a, b    *rope
+	current int
+	cached  byte
+}
+
+func (r *ropes) Len() (int, int) {
+	return r.a.length, r.b.length
+}
+
+func (r *ropes) Equal(i, j int) bool {
+	if i != r.current {
+		r.current, r.cached = i, r.a.index(i)
+	}
+	return r.cached == r.b.index(j)
+}
+
+func ExampleInterface_rope() {
+	var a *rope = newRope("the quick ", "brown fox")
+	var b *rope = newRope("the ", "quick red ", "fox")
+
+	var data *ropes = &ropes{a: a, b: b, current: -1}
+	fmt.Println(diff.Diff(data))
+	// Output: {[{11 13}] [{10 11} {12 15}]}
+}
+
+func ExampleDiffRepeated() {
+	// The repeated field of two versions of a message
+	type Item struct {
+		Id    string
+		Price int
+	}
+	var before []Item = []Item{{"apple", 3}, {"pear", 4}, {"plum", 2}}
+	var after []Item = []Item{{"apple", 5}, {"plum", 2}, {"kiwi", 1}}
+
+	var delta diff.Delta = diff.DiffRepeated(before, after, func(item Item) any {
+		return item.Id
+	})
+	for _, m := range delta.Removed {
+		fmt.Println("removed", before[m.From:m.Length])
+	}
+	for _, m := range delta.Added {
+		fmt.Println("added", after[m.From:m.Length])
+	}
+	// Output:
+	// removed [{pear 4}]
+	// added [{kiwi 1}]
+}
@@ -0,0 +1,23 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Diffs two sequences of the given lengths by their 64-bit fingerprints,
+// as reported by fp for the elements of the first (side 0) or the second
+// (side 1) sequence. Each element is fingerprinted exactly once, so that
+// expensive elements are hashed in O(len1+len2) calls instead of being
+// compared deeply O(len1*len2) times.
+//
+// Elements are assumed equal when their fingerprints are, i.e. collisions
+// are assumed not to happen. If they might, a non-nil equal verifies
+// the elements whose fingerprints match and breaks such ties.
+func DiffFingerprint(len1, len2 int, fp func(i, side int) uint64, equal func(i, j int) bool) Delta {
+	var a, b []uint64 = make([]uint64, len1), make([]uint64, len2)
+	for i := range a {
+		a[i] = fp(i, 0)
+	}
+	for j := range b {
+		b[j] = fp(j, 1)
+	}
+	return Diff(WithEqual(len1, len2, func(i, j int) bool {
+		return a[i] == b[j] && (equal == nil || equal(i, j))
+	}))
+}
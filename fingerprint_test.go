@@ -0,0 +1,39 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffFingerprint(t *testing.T) {
+	var seq1, seq2 string = "abcdefgh", "abxdefyh"
+	var calls int
+	var fp = func(i, side int) uint64 {
+		calls++
+		if side == 0 {
+			return uint64(seq1[i])
+		}
+		return uint64(seq2[i])
+	}
+
+	var expected Delta = Diff(WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	}))
+	if delta := DiffFingerprint(len(seq1), len(seq2), fp, nil); fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+	if calls != len(seq1)+len(seq2) {
+		t.Errorf("Unexpected number of fingerprints\nGot %d\nExpected %d", calls, len(seq1)+len(seq2))
+	}
+
+	// A colliding fingerprint is told apart by the verifier
+	var collide = func(i, side int) uint64 {
+		return uint64(i % 4)
+	}
+	var verified Delta = DiffFingerprint(len(seq1), len(seq2), collide, func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+	if fmt.Sprint(verified) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected verified delta\nGot %v\nExpected %v", verified, expected)
+	}
+}
@@ -0,0 +1,310 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// A hunk groups nearby change regions together with their surrounding context
+type hunk struct {
+	box
+	changes []box
+}
+
+// Groups the change regions of the delta into hunks with up to context
+// unchanged elements around them. Regions that are separated by no more
+// than twice the context end up in the same hunk.
+func (d Delta) hunks(len1, len2, context int) []hunk {
+	if context < 0 {
+		context = 0
+	}
+	var result []hunk
+	for _, c := range d.changes() {
+		if n := len(result); n > 0 && c.x-result[n-1].lenX <= context {
+			// Close enough to extend the previous hunk
+			result[n-1].changes = append(result[n-1].changes, c)
+			result[n-1].lenX, result[n-1].lenY = min(c.lenX+context, len1), min(c.lenY+context, len2)
+			continue
+		}
+		var before int = min(context, min(c.x, c.y))
+		result = append(result, hunk{
+			box{point{c.x - before, c.y - before}, min(c.lenX+context, len1), min(c.lenY+context, len2)},
+			[]box{c},
+		})
+	}
+	return result
+}
+
+// Escape sequences used to highlight the parts of a formatted diff
+type style struct {
+	header, removed, added, reset string
+}
+
+var ansi style = style{header: "\x1b[36m", removed: "\x1b[31m", added: "\x1b[32m", reset: "\x1b[0m"}
+
+// Formats the delta between the two sequences of lines as a unified diff
+// with up to context unchanged lines around each hunk. Only the hunks
+// are written, without the file name header lines.
+func FormatUnified(a, b []string, d Delta, context int) string {
+	return formatUnified(len(a), len(b), lineOf(a), lineOf(b), d, context, style{})
+}
+
+// Formats the delta between the two sequences as a unified diff like
+// FormatUnified, rendering each element as a line through the stringer.
+// Only the elements that end up in the output are rendered.
+func FormatUnifiedFunc[T any](a, b []T, d Delta, context int, str func(T) string) string {
+	return formatUnified(len(a), len(b), func(i int) string { return str(a[i]) },
+		func(i int) string { return str(b[i]) }, d, context, style{})
+}
+
+// Diffs the two sequences of lines and yields the hunks of their unified
+// diff one at a time, formatted as by FormatUnified, so that a large diff
+// can be streamed without buffering all of it. The delta is computed
+// upfront, each hunk is formatted only when it is requested.
+func Hunks(a, b []string, context int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var delta Delta = DiffLines(a, b, DiffOptions{})
+		for _, h := range delta.hunks(len(a), len(b), context) {
+			var builder strings.Builder
+			writeHunk(&builder, lineOf(a), lineOf(b), h, style{})
+			if !yield(builder.String()) {
+				return
+			}
+		}
+	}
+}
+
+// Returns an accessor for the lines
+func lineOf(lines []string) func(int) string {
+	return func(i int) string {
+		return lines[i]
+	}
+}
+
+// Formats the delta between the two sequences of lines as a unified diff
+// like FormatUnified, additionally highlighting hunk headers, removed
+// and added lines with ANSI color codes. Coloring can be disabled,
+// e.g. when the output is not a terminal.
+func FormatANSI(a, b []string, d Delta, context int, color bool) string {
+	if !color {
+		return FormatUnified(a, b, d, context)
+	}
+	return formatUnified(len(a), len(b), lineOf(a), lineOf(b), d, context, ansi)
+}
+
+func formatUnified(len1, len2 int, a, b func(int) string, d Delta, context int, s style) string {
+	var builder strings.Builder
+	for _, h := range d.hunks(len1, len2, context) {
+		writeHunk(&builder, a, b, h, s)
+	}
+	return builder.String()
+}
+
+// Writes a single unified diff hunk, including its header
+func writeHunk(builder *strings.Builder, a, b func(int) string, h hunk, s style) {
+	fmt.Fprintf(builder, "%s@@ -%s +%s @@%s\n", s.header, unifiedRange(h.x, h.lenX), unifiedRange(h.y, h.lenY), s.reset)
+	var x int = h.x
+	for _, c := range h.changes {
+		writeLines(builder, " ", a, x, c.x, "", "")
+		writeLines(builder, "-", a, c.x, c.lenX, s.removed, s.reset)
+		writeLines(builder, "+", b, c.y, c.lenY, s.added, s.reset)
+		x = c.lenX
+	}
+	writeLines(builder, " ", a, x, h.lenX, "", "")
+}
+
+// Writes the lines in [from, to) with the given prefix and color
+func writeLines(builder *strings.Builder, prefix string, line func(int) string, from, to int, color, reset string) {
+	for i := from; i < to; i++ {
+		builder.WriteString(color)
+		builder.WriteString(prefix)
+		builder.WriteString(line(i))
+		builder.WriteString(reset)
+		builder.WriteByte('\n')
+	}
+}
+
+// Formats a hunk range as a 1-based line number and a count,
+// omitting the count when it is one. Empty ranges refer
+// to the line that precedes them, as in GNU diff.
+func unifiedRange(from, to int) string {
+	switch to - from {
+	case 0:
+		return fmt.Sprintf("%d,0", from)
+	case 1:
+		return fmt.Sprint(from + 1)
+	default:
+		return fmt.Sprintf("%d,%d", from+1, to-from)
+	}
+}
+
+// Formats the delta between the two sequences of lines as an ed script,
+// like diff -e, that turns the first sequence into the second one when
+// fed to ed along with a final w command. The changes are written back
+// to front so that each command addresses the lines of the first sequence
+// by their original numbers. Added lines consisting of a single dot would
+// end the text input of an a or c command and are not supported.
+func FormatEd(a, b []string, d Delta) string {
+	var builder strings.Builder
+	var changes []box = d.changes()
+	for k := len(changes) - 1; k >= 0; k-- {
+		var c box = changes[k]
+		switch {
+		case c.lenX == c.x:
+			fmt.Fprintf(&builder, "%da\n", c.x)
+		case c.lenY == c.y:
+			fmt.Fprintf(&builder, "%sd\n", edRange(c.x, c.lenX))
+			continue
+		default:
+			fmt.Fprintf(&builder, "%sc\n", edRange(c.x, c.lenX))
+		}
+		writeLines(&builder, "", lineOf(b), c.y, c.lenY, "", "")
+		builder.WriteString(".\n")
+	}
+	return builder.String()
+}
+
+// Formats a non-empty range as the 1-based numbers of its first
+// and last line, omitting the last one when they are the same
+func edRange(from, to int) string {
+	if to-from == 1 {
+		return fmt.Sprint(from + 1)
+	}
+	return fmt.Sprintf("%d,%d", from+1, to)
+}
+
+// Formats the delta between the two sequences of lines in two columns like
+// diff -y, fitting each output line into width characters. Lines of the first
+// sequence are on the left and those of the second on the right, aligned per
+// the delta. The gutter between them marks changed lines with "|", removed
+// ones with "<" and added ones with ">". Changed regions pair their removed
+// and added lines up in order. Lines that do not fit their column are
+// truncated, the rest are padded, counting runes rather than bytes.
+func FormatSideBySide(a, b []string, d Delta, width int) string {
+	var column int = max(1, (width-3)/2)
+	var builder strings.Builder
+	var row = func(left, marker, right string) {
+		var line string = fit(left, column) + " " + marker + " " + fit(right, column)
+		builder.WriteString(strings.TrimRight(line, " "))
+		builder.WriteByte('\n')
+	}
+
+	var x, y int
+	var keep = func(to int) {
+		for ; x < to; x, y = x+1, y+1 {
+			row(a[x], " ", b[y])
+		}
+	}
+	for _, c := range d.changes() {
+		keep(c.x)
+		for ; x < c.lenX || y < c.lenY; x, y = x+1, y+1 {
+			switch {
+			case x < c.lenX && y < c.lenY:
+				row(a[x], "|", b[y])
+			case x < c.lenX:
+				row(a[x], "<", "")
+				y--
+			default:
+				row("", ">", b[y])
+				x--
+			}
+		}
+		x, y = c.lenX, c.lenY
+	}
+	keep(len(a))
+	return builder.String()
+}
+
+// Truncates or pads the text with spaces to the given number of runes
+func fit(text string, length int) string {
+	var runes []rune = []rune(text)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return text + strings.Repeat(" ", length-len(runes))
+}
+
+// A Suggestion struct replaces the lines StartLine to EndLine of the first
+// sequence, numbered from 1 and inclusive, with NewText, as a review
+// platform's suggested change does
+type Suggestion struct {
+	StartLine, EndLine int
+	NewText            string
+}
+
+// Returns a suggestion for each changed region of the delta between the two
+// sequences of lines, with the lines of the second sequence joined by
+// newlines as the new text. Deletions suggest an empty text. As suggestions
+// must cover at least one line, pure insertions are anchored to the line
+// that precedes them, which is repeated ahead of the inserted lines, or to
+// the following one at the start. Regions that end up sharing an anchor
+// line are merged into a single suggestion, as suggestions must not
+// overlap. Only an insertion into an empty first sequence covers
+// no lines, with EndLine being StartLine - 1.
+func Suggestions(a, b []string, d Delta) []Suggestion {
+	var regions []box
+	for _, c := range d.changes() {
+		switch {
+		case c.x < c.lenX || len(a) == 0:
+		case c.x > 0:
+			// The preceding line is kept, so it precedes the insertion in both
+			c.x, c.y = c.x-1, c.y-1
+		default:
+			c.lenX, c.lenY = c.lenX+1, c.lenY+1
+		}
+		if n := len(regions); n > 0 && c.x < regions[n-1].lenX {
+			regions[n-1].lenX, regions[n-1].lenY = max(regions[n-1].lenX, c.lenX), max(regions[n-1].lenY, c.lenY)
+			continue
+		}
+		regions = append(regions, c)
+	}
+
+	var result []Suggestion
+	for _, r := range regions {
+		result = append(result, Suggestion{r.x + 1, r.lenX, strings.Join(b[r.y:r.lenY], "\n")})
+	}
+	return result
+}
+
+// Diffs the provided data and returns the number of hunks that a unified
+// diff with up to context unchanged elements around the changes has,
+// without formatting it. Changes that are separated by no more than
+// twice the context are counted as a single hunk.
+func HunkCount(data Interface, context int) int {
+	var len1, len2 = data.Len()
+	return len(Diff(data).hunks(len1, len2, context))
+}
+
+// A Section struct groups the nearby changes of a delta under a Header,
+// the unchanged line of the first sequence that precedes them,
+// at index Line, or -1 and an empty header if the changes start it
+type Section struct {
+	Header string
+	Line   int
+	Delta
+}
+
+// Returns the changes of the delta between the two sequences of lines,
+// grouped as the hunks of a unified diff with up to context unchanged
+// lines around them, each anchored on the unchanged line before it.
+// The marks of every section keep their offsets in the whole sequences.
+func Sections(a, b []string, d Delta, context int) []Section {
+	var result []Section
+	for _, h := range d.hunks(len(a), len(b), context) {
+		var section Section = Section{Line: h.changes[0].x - 1}
+		if section.Line >= 0 {
+			section.Header = a[section.Line]
+		}
+		for _, c := range h.changes {
+			if c.x < c.lenX {
+				section.Removed = append(section.Removed, Mark{c.x, c.lenX})
+			}
+			if c.y < c.lenY {
+				section.Added = append(section.Added, Mark{c.y, c.lenY})
+			}
+		}
+		result = append(result, section)
+	}
+	return result
+}
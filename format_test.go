@@ -0,0 +1,236 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFormatUnified(t *testing.T) {
+	var a []string = strings.Split("a b c d e f g h i j k l", " ")
+	var b []string = strings.Split("a b X d e f g h i j l m", " ")
+
+	data := []struct {
+		context int
+		output  string
+	}{
+		{0, "@@ -3 +3 @@\n-c\n+X\n@@ -11 +10,0 @@\n-k\n@@ -12,0 +12 @@\n+m\n"},
+		{1, "@@ -2,3 +2,3 @@\n b\n-c\n+X\n d\n@@ -10,3 +10,3 @@\n j\n-k\n l\n+m\n"},
+		{4, "@@ -1,12 +1,12 @@\n a\n b\n-c\n+X\n d\n e\n f\n g\n h\n i\n j\n-k\n l\n+m\n"},
+	}
+
+	for _, testCase := range data {
+		if output := FormatUnified(a, b, DiffLines(a, b, DiffOptions{}), testCase.context); output != testCase.output {
+			t.Errorf("Unexpected output for context %d\nGot\n%s\nExpected\n%s", testCase.context, output, testCase.output)
+		}
+	}
+}
+
+func TestHunks(t *testing.T) {
+	var a []string = strings.Split("a b c d e f g h i j k l", " ")
+	var b []string = strings.Split("a b X d e f g h i j l m", " ")
+
+	for _, context := range []int{0, 1, 4} {
+		var hunks []string
+		for hunk := range Hunks(a, b, context) {
+			hunks = append(hunks, hunk)
+		}
+		var expected string = FormatUnified(a, b, DiffLines(a, b, DiffOptions{}), context)
+		if strings.Join(hunks, "") != expected {
+			t.Errorf("Unexpected hunks for context %d\nGot\n%s\nExpected\n%s", context, strings.Join(hunks, ""), expected)
+		}
+		if context == 0 && len(hunks) != 3 {
+			t.Errorf("Expected 3 separate hunks, got %d", len(hunks))
+		}
+	}
+
+	// Stopping early is honored
+	for range Hunks(a, b, 0) {
+		break
+	}
+}
+
+func TestFormatANSI(t *testing.T) {
+	var a []string = []string{"a", "b", "c"}
+	var b []string = []string{"a", "X", "c"}
+	var delta Delta = DiffLines(a, b, DiffOptions{})
+
+	var colored string = FormatANSI(a, b, delta, 1, true)
+	if !strings.Contains(colored, "\x1b[31m-b\x1b[0m") || !strings.Contains(colored, "\x1b[32m+X\x1b[0m") {
+		t.Errorf("Expected colored changes, got %q", colored)
+	}
+	var plain string = FormatANSI(a, b, delta, 1, false)
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("Expected no color codes, got %q", plain)
+	}
+	if plain != FormatUnified(a, b, delta, 1) {
+		t.Errorf("Expected uncolored output to match the unified format, got %q", plain)
+	}
+}
+
+func TestFormatUnifiedFunc(t *testing.T) {
+	var a []int = []int{1, 2, 3, 4, 5}
+	var b []int = []int{1, 2, 30, 4, 5, 6}
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))
+
+	var expected string = "@@ -2,4 +2,5 @@\n 2\n-3\n+30\n 4\n 5\n+6\n"
+	if output := FormatUnifiedFunc(a, b, delta, 1, strconv.Itoa); output != expected {
+		t.Errorf("Unexpected output\nGot\n%s\nExpected\n%s", output, expected)
+	}
+}
+
+func TestFormatEd(t *testing.T) {
+	var a []string = strings.Split("a b c d e f g h i j k l", " ")
+	data := []string{
+		"a b X d e f g h i j l m",
+		"new a b c d e f g h i j k l",
+		"a b c d e f g h",
+		"x y z",
+		"",
+		"a b c d e f g h i j k l",
+	}
+
+	for _, joined := range data {
+		var b []string = strings.Fields(joined)
+		var script string = FormatEd(a, b, DiffLines(a, b, DiffOptions{}))
+		result, err := ed(a, script)
+		if err != nil {
+			t.Errorf("Unexpected error for script\n%s\n%v", script, err)
+			continue
+		}
+		if strings.Join(result, " ") != strings.Join(b, " ") {
+			t.Errorf("Unexpected result of script\n%s\nGot %v\nExpected %v", script, result, b)
+		}
+	}
+
+	var expected string = "12a\nm\n.\n11d\n3c\nX\n.\n"
+	var b []string = strings.Split("a b X d e f g h i j l m", " ")
+	if script := FormatEd(a, b, DiffLines(a, b, DiffOptions{})); script != expected {
+		t.Errorf("Unexpected script\nGot\n%s\nExpected\n%s", script, expected)
+	}
+}
+
+// Runs the a, c and d commands of an ed script on the lines
+func ed(lines []string, script string) ([]string, error) {
+	var result []string = append([]string(nil), lines...)
+	var commands []string = strings.Split(strings.TrimSuffix(script, "\n"), "\n")
+	for k := 0; k < len(commands) && commands[k] != ""; k++ {
+		var command string = commands[k]
+		var op byte = command[len(command)-1]
+		var bounds []string = strings.SplitN(command[:len(command)-1], ",", 2)
+		from, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		var to int = from
+		if len(bounds) == 2 {
+			if to, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+
+		var text []string
+		if op == 'a' || op == 'c' {
+			for k++; k < len(commands) && commands[k] != "."; k++ {
+				text = append(text, commands[k])
+			}
+		}
+		switch op {
+		case 'a':
+			result = append(result[:from], append(text, result[from:]...)...)
+		case 'c', 'd':
+			result = append(result[:from-1], append(text, result[to:]...)...)
+		default:
+			return nil, fmt.Errorf("unknown command %q", command)
+		}
+	}
+	return result, nil
+}
+
+func TestFormatSideBySide(t *testing.T) {
+	var a []string = []string{"alpha", "beta", "gamma", "delta", "epsilon"}
+	var b []string = []string{"alpha", "BETA", "gamma", "epsilon", "zeta", "a very long line"}
+	var delta Delta = DiffLines(a, b, DiffOptions{})
+
+	var expected string = "" +
+		"alpha      alpha\n" +
+		"beta     | BETA\n" +
+		"gamma      gamma\n" +
+		"delta    <\n" +
+		"epsilon    epsilon\n" +
+		"         > zeta\n" +
+		"         > a very l\n"
+	if output := FormatSideBySide(a, b, delta, 19); output != expected {
+		t.Errorf("Unexpected output\nGot\n%s\nExpected\n%s", output, expected)
+	}
+
+	// The gutter is at the same offset on every line
+	for _, line := range strings.Split(strings.TrimSuffix(FormatSideBySide(a, b, delta, 40), "\n"), "\n") {
+		if len(line) > 40 || (len(line) > 20 && (line[18] != ' ' || line[20] != ' ')) {
+			t.Errorf("Unexpected alignment of line [%s]", line)
+		}
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	data := []struct {
+		a, b     string
+		expected []Suggestion
+	}{
+		{"a b c", "a b c", nil},
+		// Replacements, merged when adjacent
+		{"a b c d", "a X Y d", []Suggestion{{2, 3, "X\nY"}}},
+		// Deletions
+		{"a b c d", "a d", []Suggestion{{2, 3, ""}}},
+		// Insertions, anchored to the preceding line or the following one at the start
+		{"a b c", "a b N c", []Suggestion{{2, 2, "b\nN"}}},
+		{"a b c", "N a b c", []Suggestion{{1, 1, "N\na"}}},
+		{"", "N", []Suggestion{{1, 0, "N"}}},
+		// Insertions that share their anchor line are merged
+		{"a b", "N a M b", []Suggestion{{1, 1, "N\na\nM"}}},
+		// Several regions
+		{"a b c d e", "X b c e f", []Suggestion{{1, 1, "X"}, {4, 4, ""}, {5, 5, "e\nf"}}},
+	}
+
+	for _, testCase := range data {
+		var a, b []string = strings.Fields(testCase.a), strings.Fields(testCase.b)
+		if suggestions := Suggestions(a, b, DiffLines(a, b, DiffOptions{})); fmt.Sprintf("%q", suggestions) != fmt.Sprintf("%q", testCase.expected) {
+			t.Errorf("Unexpected suggestions for [%s] [%s]\nGot %q\nExpected %q", testCase.a, testCase.b, suggestions, testCase.expected)
+		}
+	}
+}
+
+func TestHunkCount(t *testing.T) {
+	// Changes at 2 and 4 are close, the one at 15 is distant
+	var seq1, seq2 string = "abcdefghijklmnopqrst", "abXdYfghijklmnoZqrst"
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	for context, expected := range []int{3, 2, 2, 2, 2, 1} {
+		if count := HunkCount(data, context); count != expected {
+			t.Errorf("Unexpected hunk count for context %d\nGot %d\nExpected %d", context, count, expected)
+		}
+	}
+	if count := HunkCount(WithEqual(0, 0, nil), 3); count != 0 {
+		t.Errorf("Expected no hunks for empty sequences, got %d", count)
+	}
+}
+
+func TestSections(t *testing.T) {
+	var a, b []string = strings.Fields("a b c d e f g h i"), strings.Fields("X b c d e f Y h i Z")
+	var expected []Section = []Section{
+		{"", -1, Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{0, 1}}}},
+		// The insertion at the end is within the context of the replaced g
+		{"f", 5, Delta{Added: []Mark{Mark{6, 7}, Mark{9, 10}}, Removed: []Mark{Mark{6, 7}}}},
+	}
+	if sections := Sections(a, b, DiffLines(a, b, DiffOptions{}), 1); fmt.Sprintf("%q", sections) != fmt.Sprintf("%q", expected) {
+		t.Errorf("Unexpected sections\nGot %q\nExpected %q", sections, expected)
+	}
+	if sections := Sections(a, a, DiffLines(a, a, DiffOptions{}), 1); sections != nil {
+		t.Errorf("Unexpected sections for identical input\nGot %q", sections)
+	}
+}
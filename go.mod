@@ -1,5 +1,5 @@
 module github.com/spaskalev/diff
 
-go 1.14
+go 1.23
 
 require github.com/spaskalev/bits v0.0.0-20200506124738-2089865c8ee0
@@ -0,0 +1,42 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A recorded recursive call with the match it split its bounds on
+type call struct {
+	bounds box
+	match  match
+	parent int
+}
+
+// Diffs the provided data and returns its recursion tree in Graphviz DOT
+// format, as a debugging and teaching aid. Each call of the divide and
+// conquer is a node labelled with the ranges of its bounds in both sequences
+// and the match that it split them on, with edges leading to the calls
+// for the regions before and after the match. Calls without a match
+// end the recursion and are leaves.
+func DiffGraphviz(data Interface) string {
+	var len1, len2 = data.Len()
+	var mx *matrix = Config{}.matrix(len1, len2)
+	mx.record, mx.parent = true, -1
+	mx.fill(data, 0, len1)
+	mx.recursiveDiff(box{point{0, 0}, len1, len2}, 1)
+
+	var builder strings.Builder
+	builder.WriteString("digraph diff {\n")
+	for id, c := range mx.calls {
+		var label string = fmt.Sprintf("[%d, %d) x [%d, %d)", c.bounds.x, c.bounds.lenX, c.bounds.y, c.bounds.lenY)
+		if c.match.length > 0 {
+			label += fmt.Sprintf("\\nmatch at %d, %d of %d", c.match.x, c.match.y, c.match.length)
+		}
+		fmt.Fprintf(&builder, "\tn%d [label=\"%s\"];\n", id, label)
+		if c.parent >= 0 {
+			fmt.Fprintf(&builder, "\tn%d -> n%d;\n", c.parent, id)
+		}
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
@@ -0,0 +1,31 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffGraphviz(t *testing.T) {
+	var seq1, seq2 string = "abcd", "xbcy"
+	var input Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	var expected string = "digraph diff {\n" +
+		"\tn0 [label=\"[0, 4) x [0, 4)\\nmatch at 1, 1 of 2\"];\n" +
+		"\tn1 [label=\"[0, 1) x [0, 1)\"];\n" +
+		"\tn0 -> n1;\n" +
+		"\tn2 [label=\"[3, 4) x [3, 4)\"];\n" +
+		"\tn0 -> n2;\n" +
+		"}\n"
+	var dot string = DiffGraphviz(input)
+	if dot != expected {
+		t.Errorf("Unexpected graph\nGot %s\nExpected %s", dot, expected)
+	}
+
+	// A node per recursive call
+	var _, stats = Config{}.DiffStats(input)
+	if nodes := strings.Count(dot, "[label="); nodes != stats.Calls {
+		t.Errorf("Unexpected number of nodes\nGot %d\nExpected %d", nodes, stats.Calls)
+	}
+}
@@ -0,0 +1,50 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Diffs the lines of two gzip compressed streams, decompressing them
+// on the fly. The marks are indexed by line number in the decompressed
+// content. The streams are read in order, each to its end, and the first
+// error, e.g. on a corrupt or truncated stream, is returned along with
+// the side it occurred on. The second stream is left unread if reading
+// the first one fails.
+func DiffGzip(a, b io.Reader) (Delta, error) {
+	var lines [2][]string
+	for side, r := range []io.Reader{a, b} {
+		var err error
+		if lines[side], err = gzipLines(r); err != nil {
+			return Delta{}, fmt.Errorf("diff: reading input %d: %w", side, err)
+		}
+	}
+	return DiffLines(lines[0], lines[1], DiffOptions{}), nil
+}
+
+// Returns the lines of the decompressed stream without their line endings
+func gzipLines(r io.Reader) ([]string, error) {
+	decompressed, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer decompressed.Close()
+
+	var result []string
+	var reader *bufio.Reader = bufio.NewReader(decompressed)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			result = append(result, strings.TrimSuffix(line, "\n"))
+		}
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func compress(t *testing.T, text string) *bytes.Buffer {
+	var buffer bytes.Buffer
+	var writer *gzip.Writer = gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buffer
+}
+
+func TestDiffGzip(t *testing.T) {
+	var a, b string = "start\nload config\nserve\nstop\n", "start\nload config\nreload config\nserve\nstop"
+	delta, err := DiffGzip(compress(t, a), compress(t, b))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var expected Delta = Delta{Added: []Mark{Mark{2, 3}}}
+	if fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+
+	if _, err = DiffGzip(compress(t, a), strings.NewReader("not compressed")); err == nil || !strings.Contains(err.Error(), "input 1") {
+		t.Errorf("Expected an error for the second input, got %v", err)
+	}
+	var truncated *bytes.Buffer = compress(t, a)
+	truncated.Truncate(truncated.Len() - 4)
+	if _, err = DiffGzip(truncated, compress(t, b)); err == nil || !strings.Contains(err.Error(), "input 0") {
+		t.Errorf("Expected an error for the truncated first input, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"image"
+)
+
+// Diffs two images scanline by scanline, e.g. for regression tests of
+// rendered output, so that rows shifted up or down are told apart from
+// changed ones. The marks index rows from the top of each image's bounds.
+// Rows are compared by a 64-bit hash of their width and of their pixels'
+// colors, which is computed once per row. Rows of images with different
+// widths never match, so such images are reported as wholly replaced.
+func DiffImage(a, b image.Image) Delta {
+	var rowsA, rowsB []uint64 = rowHashes(a), rowHashes(b)
+	return Diff(WithEqual(len(rowsA), len(rowsB), func(i, j int) bool {
+		return rowsA[i] == rowsB[j]
+	}))
+}
+
+// Returns a hash of each row of the image
+func rowHashes(img image.Image) []uint64 {
+	var bounds image.Rectangle = img.Bounds()
+	var result []uint64 = make([]uint64, 0, bounds.Dy())
+	var buffer []byte = make([]byte, 16)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		var hash = fnv.New64a()
+		hash.Write(binary.LittleEndian.AppendUint64(buffer[:0], uint64(bounds.Dx())))
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var r, g, b, a = img.At(x, y).RGBA()
+			buffer = binary.LittleEndian.AppendUint32(buffer[:0], r)
+			buffer = binary.LittleEndian.AppendUint32(buffer, g)
+			buffer = binary.LittleEndian.AppendUint32(buffer, b)
+			buffer = binary.LittleEndian.AppendUint32(buffer, a)
+			hash.Write(buffer)
+		}
+		result = append(result, hash.Sum64())
+	}
+	return result
+}
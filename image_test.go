@@ -0,0 +1,47 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// Returns a grayscale image with a row of the given shade for each value
+func grayRows(width int, shades ...uint8) image.Image {
+	var img *image.Gray = image.NewGray(image.Rect(0, 0, width, len(shades)))
+	for y, shade := range shades {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{shade})
+		}
+	}
+	return img
+}
+
+func TestDiffImage(t *testing.T) {
+	data := []struct {
+		a, b  image.Image
+		delta Delta
+	}{
+		{grayRows(4, 1, 2, 3, 4), grayRows(4, 1, 2, 3, 4), Delta{}},
+		// Content shifted down by an inserted row
+		{grayRows(4, 1, 2, 3, 4, 5, 6), grayRows(4, 9, 1, 2, 3, 4, 5), Delta{Added: []Mark{Mark{0, 1}}, Removed: []Mark{Mark{5, 6}}}},
+		// A changed row
+		{grayRows(4, 1, 2, 3, 4), grayRows(4, 1, 2, 7, 4), Delta{Added: []Mark{Mark{2, 3}}, Removed: []Mark{Mark{2, 3}}}},
+		// Different widths
+		{grayRows(4, 1, 2), grayRows(5, 1, 2), Delta{Added: []Mark{Mark{0, 2}}, Removed: []Mark{Mark{0, 2}}}},
+		{grayRows(0), grayRows(3, 1), Delta{Added: []Mark{Mark{0, 1}}}},
+	}
+
+	for _, testCase := range data {
+		if delta := DiffImage(testCase.a, testCase.b); fmt.Sprint(delta) != fmt.Sprint(testCase.delta) {
+			t.Errorf("Unexpected delta for images %v and %v\nGot %v\nExpected %v", testCase.a.Bounds(), testCase.b.Bounds(), delta, testCase.delta)
+		}
+	}
+
+	// Rows are indexed from the top of the bounds
+	var sub image.Image = grayRows(4, 0, 1, 2, 3).(*image.Gray).SubImage(image.Rect(0, 1, 4, 4))
+	if delta := DiffImage(sub, grayRows(4, 1, 2, 3)); len(delta.Added) != 0 || len(delta.Removed) != 0 {
+		t.Errorf("Expected no changes for a sub-image, got %v", delta)
+	}
+}
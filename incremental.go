@@ -0,0 +1,87 @@
+package diff // import "github.com/spaskalev/diff"
+
+// The number of trailing elements of the second sequence whose alignment
+// an Incremental keeps open to revision as further elements are appended
+const incrementalHorizon = 32
+
+// An Incremental diffs a fixed first sequence against a second one that
+// grows one element at a time, e.g. a live transcript against its script.
+//
+// The delta is not recomputed from scratch on every append. Changes that
+// lie more than a horizon of 32 elements behind the end of the second
+// sequence are committed once they are followed by an aligned element and
+// are never revisited. Each append re-diffs only the rest of the first
+// sequence after the last commit against the elements of the second one
+// appended since, i.e. typically the horizon and the latest element.
+// The result is therefore approximate: a better alignment that would need
+// to revise committed changes is missed. Until anything is aligned,
+// the re-diffed part keeps growing with each append.
+type Incremental[T comparable] struct {
+	a, b []T
+	// The first sequence up to x and the second up to y are committed
+	x, y      int
+	committed Delta
+	// The changes after the committed part as of the last append
+	tail Delta
+}
+
+// Returns an Incremental for diffing against the first sequence,
+// which must not be modified while the result is in use
+func NewIncremental[T comparable](a []T) *Incremental[T] {
+	var result *Incremental[T] = &Incremental[T]{a: a}
+	if len(a) > 0 {
+		result.tail.Removed = []Mark{Mark{0, len(a)}}
+	}
+	return result
+}
+
+// Appends an element to the second sequence and updates the delta
+func (inc *Incremental[T]) Append(element T) {
+	inc.b = append(inc.b, element)
+	var a, b []T = inc.a[inc.x:], inc.b[inc.y:]
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))
+
+	// Find the furthest aligned position that lies beyond the horizon,
+	// right after at least one unchanged element
+	var changes []box = delta.changes()
+	var cut, previous point
+	var limit int = len(b) - incrementalHorizon
+	for i := 0; i <= len(changes); i++ {
+		var next point = point{len(a), len(b)}
+		if i < len(changes) {
+			next = changes[i].point
+		}
+		if k := min(next.x-previous.x, limit-previous.y); k > 0 {
+			cut = point{previous.x + k, previous.y + k}
+		}
+		if i < len(changes) {
+			previous = point{changes[i].lenX, changes[i].lenY}
+		}
+	}
+
+	inc.tail = Delta{}
+	for _, c := range changes {
+		var target *Delta = &inc.tail
+		if c.x < cut.x {
+			target = &inc.committed
+		}
+		if c.lenX > c.x {
+			target.Removed = append(target.Removed, Mark{inc.x + c.x, inc.x + c.lenX})
+		}
+		if c.lenY > c.y {
+			target.Added = append(target.Added, Mark{inc.y + c.y, inc.y + c.lenY})
+		}
+	}
+	inc.x, inc.y = inc.x+cut.x, inc.y+cut.y
+}
+
+// Returns the delta between the first sequence and the elements
+// of the second one appended so far
+func (inc *Incremental[T]) Current() Delta {
+	var result Delta
+	result.Removed = append(append([]Mark(nil), inc.committed.Removed...), inc.tail.Removed...)
+	result.Added = append(append([]Mark(nil), inc.committed.Added...), inc.tail.Added...)
+	return result
+}
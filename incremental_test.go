@@ -0,0 +1,42 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIncremental(t *testing.T) {
+	var script []string
+	for i := 0; i < 100; i++ {
+		script = append(script, fmt.Sprintf("w%d", i))
+	}
+	var transcript []string = append([]string(nil), script...)
+	// Skipped, inserted and changed words
+	transcript = append(transcript[:5], transcript[6:]...)
+	transcript = append(transcript[:40], append([]string{"um", "well"}, transcript[40:]...)...)
+	transcript[70] = "query"
+
+	var inc *Incremental[string] = NewIncremental(script)
+	if delta := inc.Current(); fmt.Sprint(delta) != fmt.Sprint(Delta{Removed: []Mark{Mark{0, len(script)}}}) {
+		t.Errorf("Expected the whole script to be removed initially, got %v", delta)
+	}
+
+	for n, word := range transcript {
+		inc.Append(word)
+		var delta Delta = inc.Current()
+		var prefix []string = transcript[:n+1]
+		if err := Verify(WithEqual(len(script), len(prefix), func(i, j int) bool {
+			return script[i] == prefix[j]
+		}), delta, nil); err != nil {
+			t.Fatalf("Unexpected invalid delta %v after %d words: %v", delta, n+1, err)
+		}
+	}
+
+	var expected Delta = Delta{Added: []Mark{Mark{40, 42}, Mark{70, 71}}, Removed: []Mark{Mark{5, 6}, Mark{69, 70}}}
+	if delta := inc.Current(); fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected final delta\nGot %v\nExpected %v", delta, expected)
+	}
+	if inc.x == 0 || inc.y == 0 {
+		t.Errorf("Expected part of the delta to be committed")
+	}
+}
@@ -0,0 +1,44 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+)
+
+// Diffs the provided data taking the known runs as matched, e.g. when
+// stable line hashes already tell which regions are unchanged. Only the
+// gaps between the known runs are compared and diffed, each on its own,
+// which is faster and keeps the delta stable around the known runs.
+// The runs must be in order, non-empty, non-overlapping in both sequences
+// and within bounds, otherwise an error is returned. Their elements
+// are not compared, so they are kept whether they are equal or not.
+func DiffWithKnownMatches(data Interface, known []Common) (Delta, error) {
+	var len1, len2 = data.Len()
+	var previous point
+	for _, c := range known {
+		if c.Length <= 0 || c.FromX < previous.x || c.FromY < previous.y || c.FromX+c.Length > len1 || c.FromY+c.Length > len2 {
+			return Delta{}, fmt.Errorf("diff: known match %v is empty, out of order or out of bounds", c)
+		}
+		previous = point{c.FromX + c.Length, c.FromY + c.Length}
+	}
+
+	var mx *matrix = Config{}.matrix(len1, len2)
+	var result Delta
+	var gap = func(bounds box) {
+		for i := bounds.x; i < bounds.lenX; i++ {
+			for j := bounds.y; j < bounds.lenY; j++ {
+				mx.v.Poke(mx.at(point{i, j}), data.Equal(i, j))
+			}
+		}
+		var delta Delta = mx.recursiveDiff(bounds, 1)
+		result.Removed = append(result.Removed, delta.Removed...)
+		result.Added = append(result.Added, delta.Added...)
+	}
+
+	previous = point{}
+	for _, c := range known {
+		gap(box{previous, c.FromX, c.FromY})
+		previous = point{c.FromX + c.Length, c.FromY + c.Length}
+	}
+	gap(box{previous, len1, len2})
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffWithKnownMatches(t *testing.T) {
+	var seq1, seq2 string = "abcXdefYabc", "defabcabc"
+	var calls int
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		calls++
+		return seq1[i] == seq2[j]
+	})
+
+	// Anchoring "def" keeps it, which the plain diff does not
+	delta, err := DiffWithKnownMatches(data, []Common{{4, 0, 3}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var expected Delta = Delta{Added: []Mark{Mark{6, 9}}, Removed: []Mark{Mark{0, 4}, Mark{7, 8}}}
+	if fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+	// Only the gaps before and after the anchor are compared
+	if expected := 4*0 + 4*6; calls != expected {
+		t.Errorf("Unexpected number of comparisons\nGot %d\nExpected %d", calls, expected)
+	}
+	if err = Verify(data, delta, nil); err != nil {
+		t.Errorf("Unexpected invalid delta %v: %v", delta, err)
+	}
+
+	if delta, err = DiffWithKnownMatches(data, nil); err != nil || fmt.Sprint(delta) != fmt.Sprint(Config{}.Diff(data)) {
+		t.Errorf("Expected the plain delta without known matches, got %v %v", delta, err)
+	}
+
+	for _, known := range [][]Common{
+		{{0, 0, 0}},
+		{{9, 8, 3}},
+		{{4, 0, 3}, {5, 4, 1}},
+		{{4, 4, 1}, {1, 5, 1}},
+		{{-1, 0, 1}},
+	} {
+		if _, err = DiffWithKnownMatches(data, known); err == nil {
+			t.Errorf("Expected an error for known matches %v", known)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Diffs the provided data and returns the delta along with the common
+// subsequence that it keeps, as pairs of aligned indices in the first
+// and second sequence in increasing order. Both are the product
+// of a single run of the algorithm.
+func DiffAndLCS(data Interface) (Delta, [][2]int) {
+	var delta, common = diffCommon(data)
+	var pairs [][2]int
+	for _, m := range common {
+		for k := 0; k < m.length; k++ {
+			pairs = append(pairs, [2]int{m.x + k, m.y + k})
+		}
+	}
+	return delta, pairs
+}
+
+// Diffs the provided data and returns the delta
+// along with the matches it was split on, in order
+func diffCommon(data Interface) (Delta, []match) {
+	var len1, len2 = data.Len()
+	var mx *matrix = Config{}.matrix(len1, len2)
+	mx.collect = true
+	mx.fill(data, 0, len1)
+	return mx.recursiveDiff(box{point{0, 0}, len1, len2}, 1), mx.common
+}
+
+// A Common struct marks a run of Length equal elements
+// starting at FromX in one sequence and at FromY in the other
+type Common struct {
+	FromX, FromY int
+	Length       int
+}
+
+// Finds repeated blocks within a single sequence by comparing it against
+// itself, with data.Equal comparing two of its elements. Returns the maximal
+// runs of at least minLength elements where the block at FromX reappears
+// at FromY. The trivial match of each element with itself is excluded
+// and each pair of blocks is reported once, with FromX < FromY.
+func SelfDuplicates(data Interface, minLength int) []Common {
+	var length, _ = data.Len()
+	if minLength < 1 {
+		minLength = 1
+	}
+
+	var result []Common
+	for offset := 1; offset < length; offset++ {
+		var run int
+		for x := 0; x+offset <= length; x++ {
+			if x+offset < length && data.Equal(x, x+offset) {
+				run++
+				continue
+			}
+			if run >= minLength {
+				result = append(result, Common{x - run, x - run + offset, run})
+			}
+			run = 0
+		}
+	}
+	return result
+}
+
+// Diffs the provided data and returns the alignment of the kept elements:
+// for each index of the first sequence the matched index in the second one
+// and vice versa, or -1 for unmatched elements. The two are mutually
+// consistent: aToB[i] == j exactly when bToA[j] == i.
+func Alignment(data Interface) (aToB []int, bToA []int) {
+	var len1, len2 = data.Len()
+	return Diff(data).align(len1, len2)
+}
+
+// A Block struct marks a run of Length equal elements starting
+// at AStart in the first sequence and at BStart in the second one
+type Block struct {
+	AStart, BStart int
+	Length         int
+}
+
+// Diffs the provided data and returns the runs of elements that the delta
+// keeps, in increasing order and without overlaps, as Python's difflib
+// get_matching_blocks does. Adjacent runs are merged and the list ends
+// with a sentinel block of zero length at the ends of both sequences.
+func MatchingBlocks(data Interface) []Block {
+	var len1, len2 = data.Len()
+	var result []Block
+	for _, s := range Diff(data).segments(len1, len2) {
+		if s.Kind == Equal {
+			result = append(result, Block{s.FromX, s.FromY, s.Length})
+		}
+	}
+	return append(result, Block{len1, len2, 0})
+}
+
+// An Opcode struct describes how to turn a[AStart:AEnd] into b[BStart:BEnd],
+// with Tag being one of "equal", "replace", "delete" or "insert"
+type Opcode struct {
+	Tag          string
+	AStart, AEnd int
+	BStart, BEnd int
+}
+
+// Diffs the provided data and returns the operations that transform the first
+// sequence into the second one, as Python's difflib get_opcodes does. The
+// operations tile both sequences in order and a replacement covers every
+// adjacent removal and addition.
+func Opcodes(data Interface) []Opcode {
+	var len1, len2 = data.Len()
+	var result []Opcode
+	var x, y int
+	for _, c := range Diff(data).changes() {
+		if c.x > x {
+			result = append(result, Opcode{"equal", x, c.x, y, c.y})
+		}
+		var tag string = "replace"
+		switch {
+		case c.x == c.lenX:
+			tag = "insert"
+		case c.y == c.lenY:
+			tag = "delete"
+		}
+		result = append(result, Opcode{tag, c.x, c.lenX, c.y, c.lenY})
+		x, y = c.lenX, c.lenY
+	}
+	if len1 > x {
+		result = append(result, Opcode{"equal", x, len1, y, len2})
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffAndLCS(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		lcs        [][2]int
+	}{
+		{"", "", nil},
+		{"abc", "xyz", nil},
+		{"abc", "abc", [][2]int{{0, 0}, {1, 1}, {2, 2}}},
+		{"abcdefgh", "abbcedfh", [][2]int{{0, 0}, {1, 1}, {2, 3}, {4, 4}, {5, 6}, {7, 7}}},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		delta, lcs := DiffAndLCS(input)
+
+		if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", Diff(input)) {
+			t.Errorf("Expected the delta to match Diff for data\n[%s]\n[%s]\nGot %v",
+				testCase.seq1, testCase.seq2, delta)
+		}
+		if fmt.Sprintf("%v", lcs) != fmt.Sprintf("%v", testCase.lcs) {
+			t.Errorf("Unexpected common subsequence for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, lcs, testCase.lcs)
+		}
+	}
+}
+
+func TestSelfDuplicates(t *testing.T) {
+	var code []string = []string{
+		"x := load()", "if x == nil {", "return err", "}", "y := x.value",
+		"print(y)",
+		"x := load()", "if x == nil {", "return err", "}", "z := x.other",
+	}
+	var data Interface = WithEqual(len(code), len(code), func(i, j int) bool {
+		return code[i] == code[j]
+	})
+
+	var expected []Common = []Common{Common{0, 6, 4}}
+	if duplicates := SelfDuplicates(data, 3); fmt.Sprint(duplicates) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected duplicates\nGot %v\nExpected %v", duplicates, expected)
+	}
+	if duplicates := SelfDuplicates(data, 5); len(duplicates) != 0 {
+		t.Errorf("Expected no duplicates above the block's length, got %v", duplicates)
+	}
+
+	var unique string = "abcdef"
+	if duplicates := SelfDuplicates(WithEqual(len(unique), len(unique), func(i, j int) bool {
+		return unique[i] == unique[j]
+	}), 1); len(duplicates) != 0 {
+		t.Errorf("Expected the trivial self-match to be excluded, got %v", duplicates)
+	}
+}
+
+func TestAlignment(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		aToB, bToA []int
+	}{
+		{"", "", []int{}, []int{}},
+		{"abc", "", []int{-1, -1, -1}, []int{}},
+		{"abcdefgh", "abbcedfh", []int{0, 1, 3, -1, 4, 6, -1, 7}, []int{0, 1, -1, 2, 4, -1, 5, 7}},
+		{"qrabcdst", "stuvwxyzabcdqr", []int{-1, -1, 8, 9, 10, 11, -1, -1}, []int{-1, -1, -1, -1, -1, -1, -1, -1, 2, 3, 4, 5, -1, -1}},
+	}
+
+	for _, testCase := range data {
+		aToB, bToA := Alignment(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}))
+
+		if fmt.Sprint(aToB, bToA) != fmt.Sprint(testCase.aToB, testCase.bToA) {
+			t.Errorf("Unexpected alignment for data\n[%s]\n[%s]\nGot %v %v\nExpected %v %v",
+				testCase.seq1, testCase.seq2, aToB, bToA, testCase.aToB, testCase.bToA)
+		}
+		for i, j := range aToB {
+			if j >= 0 && (bToA[j] != i || testCase.seq1[i] != testCase.seq2[j]) {
+				t.Errorf("Inconsistent alignment at %d for data\n[%s]\n[%s]", i, testCase.seq1, testCase.seq2)
+			}
+		}
+		for j, i := range bToA {
+			if i >= 0 && aToB[i] != j {
+				t.Errorf("Inconsistent alignment at %d for data\n[%s]\n[%s]", j, testCase.seq1, testCase.seq2)
+			}
+		}
+	}
+}
+
+func TestMatchingBlocks(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		blocks     []Block
+	}{
+		{"", "", []Block{{0, 0, 0}}},
+		{"abc", "xy", []Block{{3, 2, 0}}},
+		{"abc", "abc", []Block{{0, 0, 3}, {3, 3, 0}}},
+		{"abcdefgh", "abbcedfh", []Block{{0, 0, 2}, {2, 3, 1}, {4, 4, 1}, {5, 6, 1}, {7, 7, 1}, {8, 8, 0}}},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		if blocks := MatchingBlocks(input); fmt.Sprintf("%v", blocks) != fmt.Sprintf("%v", testCase.blocks) {
+			t.Errorf("Unexpected matching blocks for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, blocks, testCase.blocks)
+		}
+	}
+}
+
+func TestOpcodes(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		opcodes    []Opcode
+	}{
+		{"", "", nil},
+		{"abc", "abc", []Opcode{{"equal", 0, 3, 0, 3}}},
+		{"", "ab", []Opcode{{"insert", 0, 0, 0, 2}}},
+		// The example from difflib's documentation
+		{"qabxcd", "abycdf", []Opcode{
+			{"delete", 0, 1, 0, 0},
+			{"equal", 1, 3, 0, 2},
+			{"replace", 3, 4, 2, 3},
+			{"equal", 4, 6, 3, 5},
+			{"insert", 6, 6, 5, 6},
+		}},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		if opcodes := Opcodes(input); fmt.Sprintf("%v", opcodes) != fmt.Sprintf("%v", testCase.opcodes) {
+			t.Errorf("Unexpected opcodes for data\n[%s]\n[%s]\nGot %v\nExpected %v",
+				testCase.seq1, testCase.seq2, opcodes, testCase.opcodes)
+		}
+	}
+}
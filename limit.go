@@ -0,0 +1,42 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"errors"
+)
+
+// ErrComparisonLimit is returned when a diff takes more comparisons than allowed
+var ErrComparisonLimit = errors.New("diff: exceeded the comparison limit")
+
+// Diffs the provided data like Diff, bounding the number of calls to Equal,
+// e.g. when comparing elements is expensive. Once limit calls are made
+// the diff is aborted and ErrComparisonLimit is returned.
+//
+// Diff compares every pair of elements upfront, so a diff takes exactly
+// len1*len2 comparisons and the limit amounts to a bound on the input size.
+// The count only depends on the content of the sequences when elements are
+// compared on demand, see Config.CacheSize and Config.DiffMaxComparisons,
+// which is what makes a limit meaningful for expensive comparisons.
+func DiffMaxComparisons(data Interface, limit int) (Delta, error) {
+	return Config{}.DiffMaxComparisons(data, limit)
+}
+
+// Diffs the provided data using the configuration,
+// bounding the number of calls to Equal as DiffMaxComparisons does
+func (c Config) DiffMaxComparisons(data Interface, limit int) (Delta, error) {
+	var len1, len2 = data.Len()
+	var calls int
+	var exceeded = func() bool {
+		return calls > limit
+	}
+	var delta, _ = c.diffStats(WithEqual(len1, len2, func(i, j int) bool {
+		// Comparisons past the limit are answered until the diff notices
+		if calls++; exceeded() {
+			return false
+		}
+		return data.Equal(i, j)
+	}), exceeded)
+	if exceeded() {
+		return Delta{}, ErrComparisonLimit
+	}
+	return delta, nil
+}
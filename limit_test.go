@@ -0,0 +1,57 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffMaxComparisons(t *testing.T) {
+	var seq1, seq2 string = "abcdefgh", "abbcedfh"
+	var calls int
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		calls++
+		return seq1[i] == seq2[j]
+	})
+
+	// All 64 pairs are compared
+	delta, err := DiffMaxComparisons(data, 64)
+	if err != nil || fmt.Sprint(delta) != fmt.Sprint(Diff(data)) {
+		t.Errorf("Unexpected result within the limit: %v %v", delta, err)
+	}
+
+	calls = 0
+	if _, err = DiffMaxComparisons(data, 63); err != ErrComparisonLimit {
+		t.Errorf("Expected ErrComparisonLimit, got %v", err)
+	}
+	if calls != 63 {
+		t.Errorf("Unexpected number of comparisons past the limit\nGot %d\nExpected 63", calls)
+	}
+
+	// Comparing on demand takes fewer comparisons
+	var expected Delta = Config{}.Diff(data)
+	calls = 0
+	if delta, err = (Config{CacheSize: 64}).DiffMaxComparisons(data, 60); err != nil || fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected result for on demand comparisons: %v %v", delta, err)
+	}
+	if calls > 60 {
+		t.Errorf("Expected at most 60 on demand comparisons, got %d", calls)
+	}
+}
+
+func TestDiffStop(t *testing.T) {
+	// Once stopped, at most the rest of a row or a diagonal is compared
+	var length int = 100
+	for _, c := range []Config{Config{}, Config{CacheSize: 64}} {
+		var calls int
+		var data Interface = WithEqual(length, length, func(i, j int) bool {
+			calls++
+			return (i+j)%3 == 0
+		})
+		c.diffStats(data, func() bool {
+			return calls > 10
+		})
+		if calls > 10+length {
+			t.Errorf("Unexpected number of comparisons after stopping with %+v\nGot %d\nExpected at most %d", c, calls, 10+length)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+)
+
+// A DiffOptions struct controls how lines are compared by DiffLines
+type DiffOptions struct {
+	// The configuration that the lines are diffed with, held in a named field
+	// so that its methods, which know nothing of the options, are not promoted
+	Config Config
+	// Compare lines with their leading and trailing white space removed
+	TrimSpace bool
+	// Compare lines with a trailing carriage return removed, so that
+	// lines split from CRLF and LF terminated text are equal
+	IgnoreCR bool
+}
+
+// Diffs two sequences of lines, comparing them as set by the options.
+// The marks always reference the original lines.
+func DiffLines(a, b []string, opts DiffOptions) Delta {
+	a, b = opts.normalize(a), opts.normalize(b)
+	return opts.Config.Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))
+}
+
+// Returns the lines in the form they are compared in, so that
+// each line is normalized once rather than on every comparison
+func (opts DiffOptions) normalize(lines []string) []string {
+	if !opts.TrimSpace && !opts.IgnoreCR {
+		return lines
+	}
+	var result []string = make([]string, len(lines))
+	for i, line := range lines {
+		if opts.IgnoreCR {
+			line = strings.TrimSuffix(line, "\r")
+		}
+		if opts.TrimSpace {
+			line = strings.TrimSpace(line)
+		}
+		result[i] = line
+	}
+	return result
+}
+
+// Diffs two slices comparing the normalized forms of their elements, e.g.
+// rounded numbers or lowercased strings, with equal. Each element is
+// normalized once. The marks reference the original elements, so
+// elements that differ only before normalization are kept.
+func DiffNormalized[T any](a, b []T, normalize func(T) any, equal func(any, any) bool) Delta {
+	var normalA, normalB []any = make([]any, len(a)), make([]any, len(b))
+	for i := range a {
+		normalA[i] = normalize(a[i])
+	}
+	for j := range b {
+		normalB[j] = normalize(b[j])
+	}
+	return Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return equal(normalA[i], normalB[j])
+	}))
+}
+
+// A Move struct relates a line of the first sequence
+// to an identical line at a different position in the second one
+type Move struct {
+	From, To int
+}
+
+// Diffs two sequences of lines as bags of lines, e.g. for reviewing large
+// refactors that reorder code. Lines that Diff keeps in place are ignored.
+// Each line that it adds is paired with the first unpaired identical line
+// that it removes, regardless of position, and reported as a move.
+// Only the lines left unpaired are reported as added or removed,
+// in the order in which they appear.
+func DiffBagOfLines(a, b []string) (added, removed []string, moves []Move) {
+	var delta Delta = DiffLines(a, b, DiffOptions{})
+
+	var candidates map[string][]int = make(map[string][]int)
+	for _, m := range delta.Removed {
+		for i := m.From; i < m.Length; i++ {
+			candidates[a[i]] = append(candidates[a[i]], i)
+		}
+	}
+	var paired map[int]bool = make(map[int]bool)
+	for _, m := range delta.Added {
+		for j := m.From; j < m.Length; j++ {
+			if c := candidates[b[j]]; len(c) > 0 {
+				moves = append(moves, Move{c[0], j})
+				paired[c[0]] = true
+				candidates[b[j]] = c[1:]
+			} else {
+				added = append(added, b[j])
+			}
+		}
+	}
+	for _, m := range delta.Removed {
+		for i := m.From; i < m.Length; i++ {
+			if !paired[i] {
+				removed = append(removed, a[i])
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,107 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	var a []string = []string{"func main() {", "\tprintln()", "}"}
+	var b []string = []string{"func main() { ", "    println()", "}\t"}
+
+	if delta := DiffLines(a, b, DiffOptions{TrimSpace: true}); len(delta.Added)+len(delta.Removed) != 0 {
+		t.Errorf("Expected an empty delta when trimming space, got %v", delta)
+	}
+
+	var expected Delta = Delta{Added: []Mark{Mark{0, 3}}, Removed: []Mark{Mark{0, 3}}}
+	if delta := DiffLines(a, b, DiffOptions{}); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta without trimming space %v", delta)
+	}
+
+	b[1] = "\tprintln(1)"
+	expected = Delta{Added: []Mark{Mark{1, 2}}, Removed: []Mark{Mark{1, 2}}}
+	if delta := DiffLines(a, b, DiffOptions{TrimSpace: true}); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta for a changed line %v", delta)
+	}
+}
+
+func TestDiffLinesIgnoreCR(t *testing.T) {
+	var a []string = strings.Split("first\r\nsecond\r\nthird\r\n", "\n")
+	var b []string = strings.Split("first\nsecond\nchanged\n", "\n")
+
+	var expected Delta = Delta{Added: []Mark{Mark{2, 3}}, Removed: []Mark{Mark{2, 3}}}
+	if delta := DiffLines(a, b, DiffOptions{IgnoreCR: true}); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta ignoring carriage returns %v", delta)
+	}
+
+	expected = Delta{Added: []Mark{Mark{0, 3}}, Removed: []Mark{Mark{0, 3}}}
+	if delta := DiffLines(a, b, DiffOptions{}); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected strict delta %v", delta)
+	}
+}
+
+func TestDiffNormalized(t *testing.T) {
+	var a []float64 = []float64{1.01, 2.49, 3.0, 4.2}
+	var b []float64 = []float64{0.99, 2.51, 3.04, 5.0}
+	var round = func(x float64) any { return math.Round(x*10) / 10 }
+	var equal = func(x, y any) bool { return x == y }
+
+	var expected Delta = Delta{Added: []Mark{Mark{3, 4}}, Removed: []Mark{Mark{3, 4}}}
+	if delta := DiffNormalized(a, b, round, equal); fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+
+	var upper []string = []string{"Hello", "WORLD"}
+	var lower []string = []string{"hello", "world"}
+	if delta := DiffNormalized(upper, lower, func(s string) any { return strings.ToLower(s) }, equal); len(delta.Added) != 0 || len(delta.Removed) != 0 {
+		t.Errorf("Expected no changes for elements differing in case only, got %v", delta)
+	}
+}
+
+func TestMaxDrift(t *testing.T) {
+	var a []string = strings.Split("KLMNOPabcd", "")
+	var b []string = strings.Split("abcdEFGHKLMNOP", "")
+
+	data := []struct {
+		maxDrift int
+		delta    Delta
+	}{
+		// The distant run is longer and wins without a bound
+		{0, Delta{Added: []Mark{Mark{0, 8}}, Removed: []Mark{Mark{6, 10}}}},
+		{8, Delta{Added: []Mark{Mark{0, 8}}, Removed: []Mark{Mark{6, 10}}}},
+		{6, Delta{Added: []Mark{Mark{4, 14}}, Removed: []Mark{Mark{0, 6}}}},
+		{5, Delta{Added: []Mark{Mark{0, 14}}, Removed: []Mark{Mark{0, 10}}}},
+	}
+
+	for _, testCase := range data {
+		var opts DiffOptions = DiffOptions{Config: Config{MaxDrift: testCase.maxDrift}}
+		if delta := DiffLines(a, b, opts); fmt.Sprint(delta) != fmt.Sprint(testCase.delta) {
+			t.Errorf("Unexpected delta with a drift of %d\nGot %v\nExpected %v", opts.Config.MaxDrift, delta, testCase.delta)
+		}
+	}
+}
+
+func TestDiffBagOfLines(t *testing.T) {
+	var a []string = strings.Split("package p|import x|func a() {}|func b() {}|func c() {}|var v = 1", "|")
+	var b []string = strings.Split("package p|var v = 1|func c() {}|import x|func a() {}|func b() {}", "|")
+
+	added, removed, moves := DiffBagOfLines(a, b)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("Expected only moves for reordered lines, got added %q and removed %q", added, removed)
+	}
+	for _, m := range moves {
+		if a[m.From] != b[m.To] {
+			t.Errorf("Unexpected move of different lines %q and %q", a[m.From], b[m.To])
+		}
+	}
+	if len(moves) != 2 {
+		t.Errorf("Unexpected moves %v", moves)
+	}
+
+	added, removed, moves = DiffBagOfLines(strings.Split("x|y|x", "|"), strings.Split("y|x|z", "|"))
+	if fmt.Sprint(added, removed, moves) != "[z] [x] []" {
+		t.Errorf("Unexpected result for duplicate lines: %q %q %v", added, removed, moves)
+	}
+}
@@ -0,0 +1,30 @@
+package diff // import "github.com/spaskalev/diff"
+
+// A MetaEdit struct is a single removed or added element along with its value,
+// indexed in the first sequence for removals and in the second for additions
+type MetaEdit[T any] struct {
+	Kind  Kind
+	Index int
+	Value T
+}
+
+// Diffs the two slices and returns the removed and added elements
+// along with their values, so that any metadata they carry
+// is available without resolving the indices separately.
+// Within each changed region removals precede additions.
+func DiffMeta[T any](a, b []T, equal func(T, T) bool) []MetaEdit[T] {
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return equal(a[i], b[j])
+	}))
+
+	var result []MetaEdit[T]
+	for _, c := range delta.changes() {
+		for i := c.x; i < c.lenX; i++ {
+			result = append(result, MetaEdit[T]{Removed, i, a[i]})
+		}
+		for j := c.y; j < c.lenY; j++ {
+			result = append(result, MetaEdit[T]{Added, j, b[j]})
+		}
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffMeta(t *testing.T) {
+	type line struct {
+		text string
+		file string
+		num  int
+	}
+	var a []line = []line{{"a", "old.go", 1}, {"b", "old.go", 2}, {"c", "old.go", 3}}
+	var b []line = []line{{"a", "new.go", 1}, {"c", "new.go", 2}, {"d", "new.go", 3}}
+
+	var edits []MetaEdit[line] = DiffMeta(a, b, func(x, y line) bool {
+		return x.text == y.text
+	})
+
+	var expected string = "[{removed 1 {b old.go 2}} {added 2 {d new.go 3}}]"
+	if fmt.Sprint(edits) != expected {
+		t.Errorf("Unexpected edits\nGot %v\nExpected %v", edits, expected)
+	}
+}
@@ -0,0 +1,219 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Computes a minimal delta for the elements within the bounds
+// using Myers' O(ND) greedy algorithm, keeping the furthest reaching
+// path of every diagonal for each edit distance to backtrack through
+func myers(data Interface, bounds box) Delta {
+	var delta, _ = boundedMyers(data, bounds, (bounds.lenX-bounds.x)+(bounds.lenY-bounds.y))
+	return delta
+}
+
+// Computes a minimal delta for the elements within the bounds like myers,
+// giving up once the edit distance exceeds the limit. Only the diagonals
+// within the limit are visited, so this takes O((n+m) * limit) time.
+func boundedMyers(data Interface, bounds box, limit int) (Delta, bool) {
+	var n, m int = bounds.lenX - bounds.x, bounds.lenY - bounds.y
+	var offset int = n + m + 1
+	var v []int = make([]int, 2*offset+1)
+	var trace [][]int
+
+	for d := 0; d <= min(limit, n+m); d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Step down, i.e. an addition
+			} else {
+				x = v[offset+k-1] + 1 // Step right, i.e. a removal
+			}
+			var y int = x - k
+			for x < n && y < m && data.Equal(bounds.x+x, bounds.y+y) {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, offset, bounds, n, m), true
+			}
+		}
+	}
+	return Delta{}, false
+}
+
+// Walks the trace back from (n, m) and collects the edits on the way
+func backtrack(trace [][]int, offset int, bounds box, n, m int) Delta {
+	var removed, added []int
+	var x, y int = n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		var v []int = trace[d]
+		var k int = x - y
+		var previous int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previous = k + 1
+		} else {
+			previous = k - 1
+		}
+		var previousX int = v[offset+previous]
+		var previousY int = previousX - previous
+		for x > previousX && y > previousY {
+			x, y = x-1, y-1
+		}
+		if x == previousX {
+			added = append(added, bounds.y+previousY)
+		} else {
+			removed = append(removed, bounds.x+previousX)
+		}
+		x, y = previousX, previousY
+	}
+
+	var result Delta
+	for i := len(removed) - 1; i >= 0; i-- {
+		result.Removed = appendMark(result.Removed, removed[i], removed[i]+1)
+	}
+	for j := len(added) - 1; j >= 0; j-- {
+		result.Added = appendMark(result.Added, added[j], added[j]+1)
+	}
+	return result
+}
+
+// Diffs the provided data minimizing the total cost of the delta,
+// where each added element costs insCost and each removed one delCost.
+//
+// As additions and removals are the only edits, a delta that keeps k elements
+// costs delCost*len1 + insCost*len2 - (insCost+delCost)*k. For positive costs
+// the cheapest deltas are therefore exactly those that keep a longest common
+// subsequence, whatever the ratio between the costs, and one is found with
+// Myers' algorithm. The greedy Diff may keep fewer elements than that and
+// so costs insCost+delCost more for each element it misses. When keeping an
+// element saves nothing, i.e. insCost+delCost <= 0, the delta removes
+// the whole first sequence and adds the whole second one.
+func DiffAsymmetric(data Interface, insCost, delCost int) Delta {
+	var len1, len2 = data.Len()
+	if insCost+delCost <= 0 {
+		return unmatched(box{point{0, 0}, len1, len2})
+	}
+	return myers(data, box{point{0, 0}, len1, len2})
+}
+
+// Diffs the provided data if it takes at most k removed and added elements,
+// reporting false otherwise. This solves the k-difference problem
+// in O((len1+len2) * k) time instead of the quadratic time of Diff,
+// as only the diagonals within k of the main one are explored,
+// and bails out early when the lengths alone differ by more than k.
+// The returned delta is minimal.
+func DiffWithinK(data Interface, k int) (Delta, bool) {
+	var len1, len2 = data.Len()
+	if k < 0 || abs(len1-len2) > k {
+		return Delta{}, false
+	}
+	return boundedMyers(data, box{point{0, 0}, len1, len2}, k)
+}
+
+// Diffs the provided data into a minimal delta like DiffAsymmetric, but in
+// linear space: instead of keeping the furthest reaching paths of every
+// edit distance, Myers' refinement searches from both ends for the middle
+// snake of an optimal path and recurses on the two halves around it.
+// This takes O((len1+len2) * D) time for D removed and added elements,
+// as the plain algorithm does, in only O(len1+len2) memory, which makes
+// it suitable for large and mostly similar sequences.
+func DiffMyersLinear(data Interface) Delta {
+	var len1, len2 = data.Len()
+	var result Delta
+	linearMyers(data, box{point{0, 0}, len1, len2}, &result)
+	return result
+}
+
+// Appends a minimal delta for the elements within the bounds to the result
+func linearMyers(data Interface, bounds box, result *Delta) {
+	for bounds.x < bounds.lenX && bounds.y < bounds.lenY && data.Equal(bounds.x, bounds.y) {
+		bounds.x, bounds.y = bounds.x+1, bounds.y+1
+	}
+	for bounds.x < bounds.lenX && bounds.y < bounds.lenY && data.Equal(bounds.lenX-1, bounds.lenY-1) {
+		bounds.lenX, bounds.lenY = bounds.lenX-1, bounds.lenY-1
+	}
+
+	if bounds.x < bounds.lenX && bounds.y < bounds.lenY {
+		if p, ok := middleSnake(data, bounds); ok {
+			linearMyers(data, box{bounds.point, p.x, p.y}, result)
+			linearMyers(data, box{p, bounds.lenX, bounds.lenY}, result)
+			return
+		}
+	}
+	if bounds.x < bounds.lenX {
+		result.Removed = appendMark(result.Removed, bounds.x, bounds.lenX)
+	}
+	if bounds.y < bounds.lenY {
+		result.Added = appendMark(result.Added, bounds.y, bounds.lenY)
+	}
+}
+
+// Searches the bounds from both ends at once for an optimal path and returns
+// the point where the forward and the reverse searches overlap, splitting
+// the bounds in two. Both ends of the bounds are expected to differ.
+func middleSnake(data Interface, bounds box) (point, bool) {
+	var n, m int = bounds.lenX - bounds.x, bounds.lenY - bounds.y
+	var maxD int = (n + m + 1) / 2
+	var offset int = maxD
+	// The furthest reaching x of each diagonal, from the start in forward
+	// and from the end in reverse, or -1 for diagonals not reached yet
+	var forward, reverse []int = make([]int, 2*maxD+2), make([]int, 2*maxD+2)
+	for k := range forward {
+		forward[k], reverse[k] = -1, -1
+	}
+	forward[offset+1], reverse[offset+1] = 0, 0
+
+	var delta int = n - m
+	// With an odd delta the searches overlap on a forward step
+	var odd bool = delta%2 != 0
+	// Diagonals that left the bounds are trimmed off the search
+	var startF, endF, startR, endR int
+	for d := 0; d < maxD; d++ {
+		for k := -d + startF; k <= d-endF; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			var y int = x - k
+			for x < n && y < m && data.Equal(bounds.x+x, bounds.y+y) {
+				x, y = x+1, y+1
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				endF += 2
+			case y > m:
+				startF += 2
+			case odd:
+				if r := offset + delta - k; r >= 0 && r < len(reverse) && reverse[r] != -1 && x >= n-reverse[r] {
+					return point{bounds.x + x, bounds.y + y}, true
+				}
+			}
+		}
+		for k := -d + startR; k <= d-endR; k += 2 {
+			var x int
+			if k == -d || (k != d && reverse[offset+k-1] < reverse[offset+k+1]) {
+				x = reverse[offset+k+1]
+			} else {
+				x = reverse[offset+k-1] + 1
+			}
+			var y int = x - k
+			for x < n && y < m && data.Equal(bounds.lenX-x-1, bounds.lenY-y-1) {
+				x, y = x+1, y+1
+			}
+			reverse[offset+k] = x
+			switch {
+			case x > n:
+				endR += 2
+			case y > m:
+				startR += 2
+			case !odd:
+				if f := offset + delta - k; f >= 0 && f < len(forward) && forward[f] != -1 && forward[f] >= n-x {
+					var fx int = forward[f]
+					return point{bounds.x + fx, bounds.y + fx - (delta - k)}, true
+				}
+			}
+		}
+	}
+	return point{}, false
+}
@@ -0,0 +1,142 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestMyers(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		edits      int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"abcabba", "cbabac", 5},
+		{"abcdefgh", "abbcedfh", 4},
+		{"kitten", "sitting", 5},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		var delta Delta = myers(input, box{point{0, 0}, len(testCase.seq1), len(testCase.seq2)})
+		if err := Verify(input, delta, nil); err != nil {
+			t.Errorf("Unexpected invalid delta %v for [%s] [%s]: %v", delta, testCase.seq1, testCase.seq2, err)
+		}
+		if removed, added := delta.Len(); removed+added != testCase.edits {
+			t.Errorf("Expected %d edits for [%s] [%s], got %v", testCase.edits, testCase.seq1, testCase.seq2, delta)
+		}
+	}
+}
+
+func TestDiffAsymmetric(t *testing.T) {
+	// The greedy diff splits on the longest run and misses the rest
+	var seq1, seq2 string = "abcXdefYabc", "defabcabc"
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+	var cost = func(d Delta, insCost, delCost int) int {
+		var removed, added = d.Len()
+		return removed*delCost + added*insCost
+	}
+
+	for _, costs := range [][2]int{{1, 1}, {1, 5}, {5, 1}} {
+		var delta Delta = DiffAsymmetric(data, costs[0], costs[1])
+		if err := Verify(data, delta, nil); err != nil {
+			t.Fatalf("Unexpected invalid delta %v: %v", delta, err)
+		}
+		if c, greedy := cost(delta, costs[0], costs[1]), cost(Diff(data), costs[0], costs[1]); c > greedy {
+			t.Errorf("Expected a cost of at most %d for costs %v, got %d", greedy, costs, c)
+		}
+	}
+
+	if delta := DiffAsymmetric(data, 1, -1); fmt.Sprintf("%v", delta) != "{[{0 9}] [{0 11}]}" {
+		t.Errorf("Expected a full replacement when keeping saves nothing, got %v", delta)
+	}
+}
+
+func TestDiffWithinK(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		k          int
+		ok         bool
+	}{
+		{"", "", 0, true},
+		{"abc", "abc", 0, true},
+		{"kitten", "sitting", 5, true},
+		{"kitten", "sitting", 4, false},
+		{"abc", "abcdefgh", 4, false},
+		{"abcdefgh", "abbcedfh", 4, true},
+		{"abcdefgh", "abbcedfh", 3, false},
+		{"abc", "abc", -1, false},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		delta, ok := DiffWithinK(input, testCase.k)
+		if ok != testCase.ok {
+			t.Errorf("Unexpected result for [%s] [%s] within %d\nGot %v\nExpected %v", testCase.seq1, testCase.seq2, testCase.k, ok, testCase.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := Verify(input, delta, nil); err != nil {
+			t.Errorf("Unexpected invalid delta %v for [%s] [%s]: %v", delta, testCase.seq1, testCase.seq2, err)
+		}
+		if removed, added := delta.Len(); removed+added > testCase.k {
+			t.Errorf("Expected at most %d edits for [%s] [%s], got %v", testCase.k, testCase.seq1, testCase.seq2, delta)
+		}
+	}
+}
+
+func TestDiffMyersLinear(t *testing.T) {
+	var random *rand.Rand = rand.New(rand.NewSource(1))
+	var sequence = func() string {
+		var result []byte = make([]byte, random.Intn(20))
+		for k := range result {
+			result[k] = "abc"[random.Intn(3)]
+		}
+		return string(result)
+	}
+
+	for n := 0; n < 500; n++ {
+		var seq1, seq2 string = sequence(), sequence()
+		var input Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+			return seq1[i] == seq2[j]
+		})
+		var delta Delta = DiffMyersLinear(input)
+		if err := Verify(input, delta, nil); err != nil {
+			t.Fatalf("Unexpected invalid delta %v for [%s] [%s]: %v", delta, seq1, seq2, err)
+		}
+		var minimal Delta = myers(input, box{point{0, 0}, len(seq1), len(seq2)})
+		if removed, added := delta.Len(); removed+added != count(minimal.Removed)+count(minimal.Added) {
+			t.Fatalf("Unexpected non-minimal delta for [%s] [%s]\nGot %v\nExpected %v", seq1, seq2, delta, minimal)
+		}
+	}
+}
+
+func BenchmarkDiffMyersLinear(b *testing.B) {
+	// Large sequences with a change every thousand elements
+	var seq1, seq2 []int = make([]int, 100000), make([]int, 100000)
+	for i := range seq1 {
+		seq1[i], seq2[i] = i, i
+		if i%1000 == 500 {
+			seq2[i] = -i
+		}
+	}
+	var input Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		DiffMyersLinear(input)
+	}
+}
@@ -0,0 +1,53 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"sort"
+	"strings"
+)
+
+// Diffs two listings of names, e.g. the files in two directories, by set
+// membership and returns the names that appear only in the second
+// listing (added) and only in the first one (removed), in sorted order.
+// Listings are sorted internally if needed. Duplicate names are counted,
+// so a name listed twice in b and once in a is reported as added once.
+func DiffNames(a, b []string) (added, removed []string) {
+	return diffNames(a, b, func(s string) string { return s })
+}
+
+// Diffs two listings of names like DiffNames, comparing names case-insensitively.
+// The reported names keep their original spelling.
+func DiffNamesFold(a, b []string) (added, removed []string) {
+	return diffNames(a, b, strings.ToLower)
+}
+
+func diffNames(a, b []string, key func(string) string) (added, removed []string) {
+	a, b = sortedNames(a, key), sortedNames(b, key)
+	var i, j int
+	for i < len(a) && j < len(b) {
+		var ka, kb string = key(a[i]), key(b[j])
+		switch {
+		case ka == kb:
+			i, j = i+1, j+1
+		case ka < kb:
+			removed = append(removed, a[i])
+			i++
+		default:
+			added = append(added, b[j])
+			j++
+		}
+	}
+	removed = append(removed, a[i:]...)
+	added = append(added, b[j:]...)
+	return
+}
+
+// Returns the names sorted by key, copying them if they are not sorted already
+func sortedNames(names []string, key func(string) string) []string {
+	var less = func(i, j int) bool { return key(names[i]) < key(names[j]) }
+	if sort.SliceIsSorted(names, less) {
+		return names
+	}
+	names = append([]string(nil), names...)
+	sort.SliceStable(names, less)
+	return names
+}
@@ -0,0 +1,36 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffNames(t *testing.T) {
+	data := []struct {
+		a, b           []string
+		fold           bool
+		added, removed []string
+	}{
+		{nil, nil, false, nil, nil},
+		{[]string{"a.go", "b.go"}, []string{"a.go", "b.go"}, false, nil, nil},
+		{[]string{"c.go", "a.go", "b.go"}, []string{"b.go", "d.go", "a.go"}, false, []string{"d.go"}, []string{"c.go"}},
+		{[]string{"a.go"}, []string{"a.go", "a.go"}, false, []string{"a.go"}, nil},
+		{[]string{"README", "a.go"}, []string{"Readme", "a.go"}, false, []string{"Readme"}, []string{"README"}},
+		{[]string{"README", "a.go"}, []string{"Readme", "a.go"}, true, nil, nil},
+		{[]string{"B.go", "a.go"}, []string{"a.go", "c.go"}, true, []string{"c.go"}, []string{"B.go"}},
+	}
+
+	for _, testCase := range data {
+		var added, removed []string
+		if testCase.fold {
+			added, removed = DiffNamesFold(testCase.a, testCase.b)
+		} else {
+			added, removed = DiffNames(testCase.a, testCase.b)
+		}
+
+		if fmt.Sprint(added, removed) != fmt.Sprint(testCase.added, testCase.removed) {
+			t.Errorf("Unexpected result for %v and %v\nGot %v %v\nExpected %v %v",
+				testCase.a, testCase.b, added, removed, testCase.added, testCase.removed)
+		}
+	}
+}
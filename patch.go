@@ -0,0 +1,141 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+)
+
+// A Patch struct is a self-contained form of a delta that carries the
+// elements it changes, along with some unchanged context around them,
+// so that it can be applied without the second sequence
+type Patch[T any] struct {
+	// The lengths of the sequences the patch was made from
+	LenA, LenB int
+	Hunks      []PatchHunk[T]
+}
+
+// A PatchHunk struct replaces the OldLen elements that start at FromA
+// in the first sequence with the NewLen ones that start at FromB
+// in the second. Both include the same unchanged context. The elements
+// themselves are carried in Old and New unless the hunk was left out
+// of a patch's payload, in which case they are nil, see DiffPatch.
+type PatchHunk[T any] struct {
+	FromA, FromB   int
+	OldLen, NewLen int
+	Old, New       []T
+}
+
+// Returns a patch of the delta between the two sequences whose hunks
+// include up to context unchanged elements around the changes,
+// grouped as for FormatUnified
+func NewPatch[T any](a, b []T, d Delta, context int) Patch[T] {
+	var result Patch[T] = Patch[T]{LenA: len(a), LenB: len(b)}
+	for _, h := range d.hunks(len(a), len(b), context) {
+		result.Hunks = append(result.Hunks, PatchHunk[T]{h.x, h.y, h.lenX - h.x, h.lenY - h.y, a[h.x:h.lenX], b[h.y:h.lenY]})
+	}
+	return result
+}
+
+// Diffs the two slices and returns a patch like NewPatch, capturing the
+// elements of at most maxPayload added or replacing elements, context
+// excluded. Hunks are captured in order until one does not fit, after
+// which it and all the following hunks only carry their offsets and lengths.
+// Such a patch still describes where the changes are but can no longer
+// be applied on its own. A negative maxPayload captures every hunk.
+func DiffPatch[T any](a, b []T, equal func(T, T) bool, context, maxPayload int) Patch[T] {
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return equal(a[i], b[j])
+	}))
+	var result Patch[T] = NewPatch(a, b, delta, context)
+	var payload int
+	for k, h := range delta.hunks(len(a), len(b), context) {
+		for _, c := range h.changes {
+			payload += c.lenY - c.y
+		}
+		if maxPayload >= 0 && payload > maxPayload {
+			for ; k < len(result.Hunks); k++ {
+				result.Hunks[k].Old, result.Hunks[k].New = nil, nil
+			}
+			break
+		}
+	}
+	return result
+}
+
+// Reports whether the patch carries the elements of all of its hunks,
+// i.e. whether it can be applied without the second sequence
+func (p Patch[T]) IsSelfContained() bool {
+	for _, h := range p.Hunks {
+		if len(h.Old) != h.OldLen || len(h.New) != h.NewLen {
+			return false
+		}
+	}
+	return true
+}
+
+// Applies the patch to a base that may differ from the first sequence it was
+// made from, like patch does with an offset. Each hunk is placed where its
+// old elements, context included, are found in the base, looking up to fuzz
+// positions before or after where the hunk is expected, the closest place
+// first. The expected place follows the offset of the previous hunk.
+// Returns an error for the first hunk that cannot be placed
+// or that does not carry its elements.
+func (p Patch[T]) ApplyFuzzy(base []T, equal func(T, T) bool, fuzz int) ([]T, error) {
+	var result []T
+	var consumed, offset int
+	for k, h := range p.Hunks {
+		if len(h.Old) != h.OldLen || len(h.New) != h.NewLen {
+			return nil, fmt.Errorf("diff: hunk %d carries no elements", k)
+		}
+		var at int = -1
+		for distance := 0; distance <= fuzz && at < 0; distance++ {
+			for _, candidate := range [2]int{h.FromA + offset - distance, h.FromA + offset + distance} {
+				if candidate >= consumed && matchesAt(base, candidate, h.Old, equal) {
+					at = candidate
+					break
+				}
+			}
+		}
+		if at < 0 {
+			return nil, fmt.Errorf("diff: hunk %d does not apply within %d positions of %d", k, fuzz, h.FromA+offset)
+		}
+		result = append(result, base[consumed:at]...)
+		result = append(result, h.New...)
+		consumed, offset = at+len(h.Old), at-h.FromA
+	}
+	return append(result, base[consumed:]...), nil
+}
+
+// Reports whether the elements are found in the base at the given position
+func matchesAt[T any](base []T, at int, elements []T, equal func(T, T) bool) bool {
+	if at+len(elements) > len(base) {
+		return false
+	}
+	for k, element := range elements {
+		if !equal(base[at+k], element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns both sequences the patch was made from, e.g. to verify that a stored
+// patch is complete. This requires the hunks to carry every element, which
+// is the case for a self-contained patch whose context spans all unchanged
+// elements, e.g. one made with a context of max(len(a), len(b)) that forms
+// a single hunk. Reports false if any element is missing.
+func (p Patch[T]) Sequences() (a, b []T, ok bool) {
+	if !p.IsSelfContained() {
+		return nil, nil, false
+	}
+	a, b = make([]T, 0, p.LenA), make([]T, 0, p.LenB)
+	for _, h := range p.Hunks {
+		if h.FromA != len(a) || h.FromB != len(b) {
+			return nil, nil, false
+		}
+		a, b = append(a, h.Old...), append(b, h.New...)
+	}
+	if len(a) != p.LenA || len(b) != p.LenB {
+		return nil, nil, false
+	}
+	return a, b, true
+}
@@ -0,0 +1,117 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyFuzzy(t *testing.T) {
+	var a []string = strings.Fields("a b c d e f g h i j k l")
+	var b []string = strings.Fields("a b X d e f g h i j l m")
+	var patch Patch[string] = NewPatch(a, b, DiffLines(a, b, DiffOptions{}), 1)
+	var equal = func(x, y string) bool { return x == y }
+
+	data := []struct {
+		base     string
+		fuzz     int
+		expected string
+		ok       bool
+	}{
+		// Clean apply
+		{"a b c d e f g h i j k l", 0, "a b X d e f g h i j l m", true},
+		// Lines inserted and removed before the hunks shift them
+		{"0 1 a b c d e f g h i j k l", 2, "0 1 a b X d e f g h i j l m", true},
+		{"0 1 a b c d e f g h i j k l", 1, "", false},
+		{"b c d e f g h i j k l", 1, "b X d e f g h i j l m", true},
+		// Lines inserted in between shift only the following hunk
+		{"a b c d e f g h 1 i j k l", 1, "a b X d e f g h 1 i j l m", true},
+		// The context of the second hunk is gone
+		{"a b c d e f g h i k l", 3, "", false},
+	}
+
+	for _, testCase := range data {
+		result, err := patch.ApplyFuzzy(strings.Fields(testCase.base), equal, testCase.fuzz)
+		if (err == nil) != testCase.ok {
+			t.Errorf("Unexpected error for base [%s] with fuzz %d: %v", testCase.base, testCase.fuzz, err)
+			continue
+		}
+		if testCase.ok && strings.Join(result, " ") != testCase.expected {
+			t.Errorf("Unexpected result for base [%s] with fuzz %d\nGot %v\nExpected %v", testCase.base, testCase.fuzz, strings.Join(result, " "), testCase.expected)
+		}
+	}
+}
+
+func TestDiffPatch(t *testing.T) {
+	var a []string = strings.Fields("a b c d e f g h i j k l")
+	var b []string = strings.Fields("a b X d e f g h i j l m n")
+	var equal = func(x, y string) bool { return x == y }
+
+	data := []struct {
+		maxPayload    int
+		captured      int
+		selfContained bool
+	}{
+		{-1, 2, true},
+		{3, 2, true},
+		{2, 1, false},
+		{0, 0, false},
+	}
+
+	for _, testCase := range data {
+		var patch Patch[string] = DiffPatch(a, b, equal, 1, testCase.maxPayload)
+		if len(patch.Hunks) != 2 {
+			t.Fatalf("Unexpected hunks %v", patch.Hunks)
+		}
+		var captured int
+		for _, h := range patch.Hunks {
+			if h.New != nil {
+				captured++
+			}
+		}
+		if captured != testCase.captured || patch.IsSelfContained() != testCase.selfContained {
+			t.Errorf("Unexpected patch with a payload of %d\nGot %d captured hunks, %v\nExpected %d, %v",
+				testCase.maxPayload, captured, patch.IsSelfContained(), testCase.captured, testCase.selfContained)
+		}
+		if last := patch.Hunks[1]; last.FromA != 9 || last.OldLen != 3 || last.NewLen != 4 {
+			t.Errorf("Unexpected offsets for the last hunk %+v", last)
+		}
+
+		result, err := patch.ApplyFuzzy(a, equal, 0)
+		if testCase.selfContained && (err != nil || strings.Join(result, " ") != strings.Join(b, " ")) {
+			t.Errorf("Unexpected result of a self-contained patch %v: %v", result, err)
+		}
+		if !testCase.selfContained && err == nil {
+			t.Errorf("Expected an error for applying a patch without all of its elements")
+		}
+	}
+}
+
+func TestSequences(t *testing.T) {
+	var a []string = strings.Fields("a b c d e f g h i j k l")
+	var b []string = strings.Fields("a b X d e f g h i j l m")
+	var delta Delta = DiffLines(a, b, DiffOptions{})
+
+	data := []struct {
+		patch Patch[string]
+		ok    bool
+	}{
+		{NewPatch(a, b, delta, len(a)), true},
+		{NewPatch(a, a, Delta{}, len(a)), false},
+		{NewPatch([]string{}, []string{}, Delta{}, 0), true},
+		// Unchanged elements between and around the hunks are missing
+		{NewPatch(a, b, delta, 1), false},
+		{NewPatch(a, b, delta, 3), false},
+		{DiffPatch(a, b, func(x, y string) bool { return x == y }, len(a), 1), false},
+	}
+
+	for i, testCase := range data {
+		seqA, seqB, ok := testCase.patch.Sequences()
+		if ok != testCase.ok {
+			t.Errorf("Unexpected result for patch %d\nGot %v\nExpected %v", i, ok, testCase.ok)
+			continue
+		}
+		if ok && (strings.Join(seqA, " ") != strings.Join(a[:testCase.patch.LenA], " ") || strings.Join(seqB, " ") != strings.Join(b[:testCase.patch.LenB], " ")) {
+			t.Errorf("Unexpected sequences for patch %d\nGot %v %v", i, seqA, seqB)
+		}
+	}
+}
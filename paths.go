@@ -0,0 +1,81 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrHashCount is returned when the number of hashes and paths differ
+var ErrHashCount = errors.New("diff: number of hashes does not match the number of paths")
+
+// Diffs two listings of paths, e.g. two snapshots of a directory tree.
+// Listings that are both sorted, as produced by a directory walk, are
+// merged in linear time since their common subsequence is simply their
+// intersection. Other listings are diffed with Diff. The marks reference
+// the listings as given.
+func DiffPaths(a, b []string) Delta {
+	if !sort.StringsAreSorted(a) || !sort.StringsAreSorted(b) {
+		return Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+			return a[i] == b[j]
+		}))
+	}
+
+	var result Delta
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i, j = i+1, j+1
+		case a[i] < b[j]:
+			result.Removed = appendMark(result.Removed, i, i+1)
+			i++
+		default:
+			result.Added = appendMark(result.Added, j, j+1)
+			j++
+		}
+	}
+	if i < len(a) {
+		result.Removed = appendMark(result.Removed, i, len(a))
+	}
+	if j < len(b) {
+		result.Added = appendMark(result.Added, j, len(b))
+	}
+	return result
+}
+
+// A Rename struct relates a removed path in the first listing
+// to an added path in the second one with the same content
+type Rename struct {
+	From, To int
+}
+
+// Diffs two listings of paths like DiffPaths and detects renames by
+// the content hashes that are passed alongside the paths. Each added path
+// is paired with the first unpaired removed path with the same hash.
+// Renamed paths remain in the delta as a removal and an addition
+// so that it still transforms the first listing into the second.
+// ErrHashCount is returned unless there is one hash for each path.
+func DiffPathsHashed(a, b []string, hashA, hashB []string) (Delta, []Rename, error) {
+	if len(hashA) != len(a) || len(hashB) != len(b) {
+		return Delta{}, nil, ErrHashCount
+	}
+	var delta Delta = DiffPaths(a, b)
+
+	var removed map[string][]int = make(map[string][]int)
+	for _, m := range delta.Removed {
+		for i := m.From; i < m.Length; i++ {
+			removed[hashA[i]] = append(removed[hashA[i]], i)
+		}
+	}
+
+	var renames []Rename
+	for _, m := range delta.Added {
+		for j := m.From; j < m.Length; j++ {
+			if candidates := removed[hashB[j]]; len(candidates) > 0 {
+				renames = append(renames, Rename{candidates[0], j})
+				removed[hashB[j]] = candidates[1:]
+			}
+		}
+	}
+	return delta, renames, nil
+}
@@ -0,0 +1,46 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffPaths(t *testing.T) {
+	var a []string = []string{"docs/a.md", "docs/b.md", "src/main.go", "src/old.go", "src/util.go"}
+	var b []string = []string{"docs/a.md", "docs/c.md", "src/main.go", "src/new.go", "src/util.go", "src/zed.go"}
+
+	var expected Delta = Delta{
+		Added:   []Mark{Mark{1, 2}, Mark{3, 4}, Mark{5, 6}},
+		Removed: []Mark{Mark{1, 2}, Mark{3, 4}},
+	}
+	var delta Delta = DiffPaths(a, b)
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta %v, expected %v", delta, expected)
+	}
+	if err := Verify(WithEqual(len(a), len(b), func(i, j int) bool { return a[i] == b[j] }), delta, nil); err != nil {
+		t.Errorf("Unexpected invalid delta: %v", err)
+	}
+
+	var unsorted []string = []string{"src/util.go", "docs/a.md"}
+	if delta := DiffPaths(unsorted, []string{"src/util.go"}); fmt.Sprintf("%v", delta) != "{[] [{1 2}]}" {
+		t.Errorf("Unexpected delta for unsorted paths %v", delta)
+	}
+
+	// The content of old.go moved to new.go, b.md was replaced by unrelated content
+	var hashA []string = []string{"1", "2", "3", "4", "5"}
+	var hashB []string = []string{"1", "6", "3", "4", "5", "7"}
+	delta, renames, err := DiffPathsHashed(a, b, hashA, hashB)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected delta %v, expected %v", delta, expected)
+	}
+	if fmt.Sprintf("%v", renames) != "[{3 3}]" {
+		t.Errorf("Unexpected renames %v", renames)
+	}
+
+	if _, _, err = DiffPathsHashed(a, b, hashA, hashB[1:]); err != ErrHashCount {
+		t.Errorf("Expected ErrHashCount for missing hashes, got %v", err)
+	}
+}
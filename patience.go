@@ -0,0 +1,78 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"sort"
+)
+
+// Diffs the provided data by first aligning the elements that occur exactly
+// once in each sequence, as in patience diff, and then computing minimal
+// deltas between these anchors with Myers' algorithm. The occurrence
+// function reports how many times the element at idx occurs in the first
+// (side 0) or the second (side 1) sequence. Anchoring on unique elements,
+// like function signatures, keeps moved blocks from being interleaved
+// with unrelated content, which makes deltas more readable, while
+// the gaps in between are diffed minimally.
+func DiffPatienceMyers(data Interface, occurrence func(side, idx int) int) Delta {
+	var len1, len2 = data.Len()
+
+	var unique []int
+	for j := 0; j < len2; j++ {
+		if occurrence(1, j) == 1 {
+			unique = append(unique, j)
+		}
+	}
+	var pairs []point
+	for i := 0; i < len1; i++ {
+		if occurrence(0, i) != 1 {
+			continue
+		}
+		for _, j := range unique {
+			if data.Equal(i, j) {
+				pairs = append(pairs, point{i, j})
+				break
+			}
+		}
+	}
+
+	var result Delta
+	var previous point
+	for _, anchor := range patience(pairs) {
+		var gap Delta = myers(data, box{previous, anchor.x, anchor.y})
+		result.Removed = append(result.Removed, gap.Removed...)
+		result.Added = append(result.Added, gap.Added...)
+		previous = point{anchor.x + 1, anchor.y + 1}
+	}
+	var gap Delta = myers(data, box{previous, len1, len2})
+	result.Removed = append(result.Removed, gap.Removed...)
+	result.Added = append(result.Added, gap.Added...)
+	return result
+}
+
+// Returns the longest subsequence of the pairs, which are ordered
+// by x, that is increasing in y by patience sorting
+func patience(pairs []point) []point {
+	var tops []int                               // Index of the pair atop each pile
+	var previous []int = make([]int, len(pairs)) // Index of the pair atop the preceding pile
+	for p := range pairs {
+		var pile int = sort.Search(len(tops), func(i int) bool {
+			return pairs[tops[i]].y > pairs[p].y
+		})
+		previous[p] = -1
+		if pile > 0 {
+			previous[p] = tops[pile-1]
+		}
+		if pile == len(tops) {
+			tops = append(tops, p)
+		} else {
+			tops[pile] = p
+		}
+	}
+
+	var result []point = make([]point, len(tops))
+	if len(tops) > 0 {
+		for i, p := len(tops)-1, tops[len(tops)-1]; i >= 0; i, p = i-1, previous[p] {
+			result[i] = pairs[p]
+		}
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffPatienceMyers(t *testing.T) {
+	var a []string = strings.Split(strings.TrimSpace(`
+func first() {
+	return
+}
+
+func second() {
+	return
+}`), "\n")
+	var b []string = strings.Split(strings.TrimSpace(`
+func second() {
+	return
+}
+
+func first() {
+	return
+}`), "\n")
+
+	var data Interface = WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	})
+	var occurrence = func(side, idx int) int {
+		var seq []string = a
+		if side == 1 {
+			seq = b
+		}
+		var count int
+		for _, line := range seq {
+			if line == seq[idx] {
+				count++
+			}
+		}
+		return count
+	}
+
+	var delta Delta = DiffPatienceMyers(data, occurrence)
+	if err := Verify(data, delta, nil); err != nil {
+		t.Fatalf("Unexpected invalid delta %v: %v", delta, err)
+	}
+
+	// The moved function shows up as a whole block, while the plain
+	// diff swaps the signatures and keeps the other function's body
+	var expected string = "@@ -1,4 +0,0 @@\n-func first() {\n-\treturn\n-}\n-\n@@ -7,0 +4,4 @@\n+\n+func first() {\n+\treturn\n+}\n"
+	if output := FormatUnified(a, b, delta, 0); output != expected {
+		t.Errorf("Unexpected patience output\n%s", output)
+	}
+	expected = "@@ -1 +1 @@\n-func first() {\n+func second() {\n@@ -5 +5 @@\n-func second() {\n+func first() {\n"
+	if output := FormatUnified(a, b, Diff(data), 0); output != expected {
+		t.Errorf("Unexpected plain output\n%s", output)
+	}
+}
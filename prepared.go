@@ -0,0 +1,36 @@
+package diff // import "github.com/spaskalev/diff"
+
+// A Prepared struct holds a first sequence along with an index of the
+// positions of its elements, so that it can be diffed against many
+// second sequences without repeating that work
+type Prepared[T comparable] struct {
+	a     []T
+	index map[T][]int
+}
+
+// Prepares the first sequence for repeated diffs against other sequences.
+// The sequence is indexed once by its elements' values and must not
+// be modified while the result is in use.
+func Prepare[T comparable](a []T) *Prepared[T] {
+	var index map[T][]int = make(map[T][]int)
+	for i, element := range a {
+		index[element] = append(index[element], i)
+	}
+	return &Prepared[T]{a, index}
+}
+
+// Diffs the prepared sequence against the second one and returns the same
+// delta as Diff. What is shared between the diffs is the index of the
+// prepared sequence: instead of comparing every pair of elements, each
+// element of the second sequence is looked up once and only the positions
+// it matches are marked. The match matrix and the recursion over it are
+// specific to each pair and are computed anew.
+func (p *Prepared[T]) DiffAgainst(b []T) Delta {
+	var mx *matrix = Config{}.matrix(len(p.a), len(b))
+	for j, element := range b {
+		for _, i := range p.index[element] {
+			mx.v.Poke(mx.at(point{i, j}), true)
+		}
+	}
+	return mx.recursiveDiff(box{point{0, 0}, len(p.a), len(b)}, 1)
+}
@@ -0,0 +1,20 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrepared(t *testing.T) {
+	var reference string = "abcabbacbdeab"
+	var prepared *Prepared[byte] = Prepare([]byte(reference))
+
+	for _, incoming := range []string{"", "abcabbacbdeab", "abcabbxcbdeab", "cbabac", "xyz", "abcabbacbdeabab"} {
+		var expected Delta = Diff(WithEqual(len(reference), len(incoming), func(i, j int) bool {
+			return reference[i] == incoming[j]
+		}))
+		if delta := prepared.DiffAgainst([]byte(incoming)); fmt.Sprint(delta) != fmt.Sprint(expected) {
+			t.Errorf("Unexpected delta against [%s]\nGot %v\nExpected %v", incoming, delta, expected)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Diffs the provided data preferring common runs of rare elements
+// as split points, in the spirit of patience diff. The freq function
+// reports how many times the element at index i occurs in the first
+// (side 0) or the second (side 1) sequence. Each element of a run
+// weighs inversely to the product of its occurrences on both sides,
+// and the run with the highest total weight is split on. A single line
+// that is unique to both sequences thus outweighs a long run of blank lines
+// or closing braces, which anchors the alignment on meaningful content.
+// The frequencies are queried once per element.
+//
+// As shorter runs may be preferred over longer ones, the delta
+// may be larger than the one returned by Diff.
+func DiffByRarity(data Interface, freq func(i, side int) int) Delta {
+	var len1, len2 = data.Len()
+	var freq1, freq2 []int = make([]int, len1), make([]int, len2)
+	for i := range freq1 {
+		freq1[i] = freq(i, 0)
+	}
+	for j := range freq2 {
+		freq2[j] = freq(j, 1)
+	}
+
+	return Config{Score: func(x, y, length int, removed, added Mark) int {
+		var score int
+		for k := 0; k < length; k++ {
+			score += (1 << 20) / max(1, freq1[x+k]*freq2[y+k])
+		}
+		return score
+	}}.Diff(data)
+}
@@ -0,0 +1,49 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffByRarity(t *testing.T) {
+	var a []string = []string{"func a() {", "}", "}", "}"}
+	var b []string = []string{"}", "}", "}", "func b() {", "func a() {"}
+	var data Interface = WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	})
+	var freq = func(i, side int) int {
+		var seq []string = a
+		if side == 1 {
+			seq = b
+		}
+		var count int
+		for _, s := range seq {
+			if s == seq[i] {
+				count++
+			}
+		}
+		return count
+	}
+
+	// Plain diff anchors on the longest run of braces ...
+	var expected Delta = Delta{
+		Added:   []Mark{Mark{3, 5}},
+		Removed: []Mark{Mark{0, 1}},
+	}
+	if delta := Diff(data); fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected plain delta %v", delta)
+	}
+
+	// ... while the rare function header anchors the rarity diff
+	var delta Delta = DiffByRarity(data, freq)
+	if err := Verify(data, delta, nil); err != nil {
+		t.Fatalf("Unexpected invalid delta %v: %v", delta, err)
+	}
+	expected = Delta{
+		Added:   []Mark{Mark{0, 4}},
+		Removed: []Mark{Mark{1, 4}},
+	}
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected rarity delta %v", delta)
+	}
+}
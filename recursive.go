@@ -0,0 +1,40 @@
+package diff // import "github.com/spaskalev/diff"
+
+// RecursiveInterface extends Interface for sequences
+// whose elements are themselves diffable
+type RecursiveInterface interface {
+	Interface
+	// Returns the nested diff of the elements at those indices,
+	// or nil if they are too dissimilar to be related
+	SubDiff(i, j int) *Tree
+}
+
+// A Tree struct is a Delta whose replaced elements may carry nested deltas
+type Tree struct {
+	Delta
+	Nested []Nested
+}
+
+// A Nested struct relates a removed element from the first sequence
+// to an added element from the second sequence through their own diff
+type Nested struct {
+	X, Y int
+	Tree *Tree
+}
+
+// Diffs the provided data and descends into replaced elements.
+// Within each changed region the removed and added elements are paired
+// in order and each pair that SubDiff reports as related is attached
+// to the result. The nesting is as deep as the SubDiff implementation
+// goes, e.g. by calling DiffRecursive on the elements' contents.
+func DiffRecursive(data RecursiveInterface) Tree {
+	var result Tree = Tree{Delta: Diff(data)}
+	for _, c := range result.changes() {
+		for x, y := c.x, c.y; x < c.lenX && y < c.lenY; x, y = x+1, y+1 {
+			if nested := data.SubDiff(x, y); nested != nil {
+				result.Nested = append(result.Nested, Nested{x, y, nested})
+			}
+		}
+	}
+	return result
+}
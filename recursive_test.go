@@ -0,0 +1,56 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Lines of text that are diffed by line and then by character
+type lines struct {
+	a, b []string
+}
+
+func (l lines) Len() (int, int) {
+	return len(l.a), len(l.b)
+}
+
+func (l lines) Equal(i, j int) bool {
+	return l.a[i] == l.b[j]
+}
+
+// Lines that share their first character are considered related
+func (l lines) SubDiff(i, j int) *Tree {
+	var a, b string = l.a[i], l.b[j]
+	if a == "" || b == "" || a[0] != b[0] {
+		return nil
+	}
+	return &Tree{Delta: Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))}
+}
+
+func TestDiffRecursive(t *testing.T) {
+	var data lines = lines{
+		a: []string{"same", "hello world", "gone"},
+		b: []string{"same", "hello there", "else"},
+	}
+
+	var tree Tree = DiffRecursive(data)
+	if fmt.Sprintf("%v", tree.Delta) != fmt.Sprintf("%v", Diff(data)) {
+		t.Errorf("Expected the top level delta to match Diff, got %v", tree.Delta)
+	}
+	if len(tree.Nested) != 1 {
+		t.Fatalf("Expected a single nested delta, got %v", tree.Nested)
+	}
+
+	var nested Nested = tree.Nested[0]
+	if nested.X != 1 || nested.Y != 1 {
+		t.Errorf("Unexpected nested pair (%d, %d)", nested.X, nested.Y)
+	}
+	var expected Delta = Diff(WithEqual(len(data.a[1]), len(data.b[1]), func(i, j int) bool {
+		return data.a[1][i] == data.b[1][j]
+	}))
+	if len(expected.Added) == 0 || fmt.Sprintf("%v", nested.Tree.Delta) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected nested delta %v, expected %v", nested.Tree.Delta, expected)
+	}
+}
@@ -0,0 +1,85 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Diffs two repeated fields, e.g. of protobuf messages, by the identity
+// of their elements, such as an ID field. Elements with equal identities
+// are aligned and kept, the rest are removed or added. Duplicate identities
+// are aligned in order like any other repeated element. The identity of
+// each element is computed once and must be comparable with ==,
+// otherwise the comparison panics like a map lookup would.
+func DiffRepeated[T any](a, b []T, identity func(T) any) Delta {
+	var idA, idB []any = make([]any, len(a)), make([]any, len(b))
+	for i := range a {
+		idA[i] = identity(a[i])
+	}
+	for j := range b {
+		idB[j] = identity(b[j])
+	}
+	return Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return idA[i] == idB[j]
+	}))
+}
+
+// Diffs two slices by the keys that key extracts from their elements,
+// e.g. an ID field of a struct. Elements with equal keys are aligned
+// and kept even if their other fields differ, the rest are removed
+// or added. Changes to the content of a kept element are therefore
+// not reported by the delta alone, see Modified for detecting them.
+// The key of each element is computed once.
+func DiffByKey[T any, K comparable](a, b []T, key func(T) K) Delta {
+	var keyA, keyB []K = make([]K, len(a)), make([]K, len(b))
+	for i := range a {
+		keyA[i] = key(a[i])
+	}
+	for j := range b {
+		keyB[j] = key(b[j])
+	}
+	return Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return keyA[i] == keyB[j]
+	}))
+}
+
+// A KeyedChanges struct completes a key-based delta with the kept elements,
+// split by whether their content changed. Each kept element is listed
+// as a pair of its index in the first and in the second sequence.
+type KeyedChanges struct {
+	Delta
+	Unchanged [][2]int
+	Modified  [][2]int
+}
+
+// Splits the elements that the delta keeps into unchanged and modified ones
+// by comparing their full content with equal, e.g. for a delta returned by
+// DiffByKey. Together with the delta's additions and removals this gives
+// the complete picture of how the second slice differs from the first.
+func Modified[T any](a, b []T, d Delta, equal func(T, T) bool) KeyedChanges {
+	var result KeyedChanges = KeyedChanges{Delta: d}
+	var aToB, _ = d.align(len(a), len(b))
+	for i, j := range aToB {
+		if j < 0 {
+			continue
+		}
+		if equal(a[i], b[j]) {
+			result.Unchanged = append(result.Unchanged, [2]int{i, j})
+		} else {
+			result.Modified = append(result.Modified, [2]int{i, j})
+		}
+	}
+	return result
+}
+
+// Diffs two slices whose elements belong to equivalence groups, e.g. clusters,
+// treating elements of the same group as equal. An element that is replaced
+// by another member of its group is therefore kept and no change is reported
+// for it, whatever the payloads of the two elements.
+func DiffGroups[T any](a, b []T, groupID func(T) int) Delta {
+	return DiffByKey(a, b, groupID)
+}
+
+// Diffs two slices of records by the values that project extracts from them,
+// which may combine several fields. P must be comparable, so a projection
+// of multiple fields is best returned as a struct of those fields, e.g.
+// struct{ Name string; Age int }, rather than as a slice or a map.
+// The marks index the original records, so whole rows can be shown.
+func DiffProjected[T any, P comparable](a, b []T, project func(T) P) Delta {
+	return DiffByKey(a, b, project)
+}
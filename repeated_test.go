@@ -0,0 +1,73 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+type keyed struct {
+	id    int
+	value string
+}
+
+func TestDiffByKey(t *testing.T) {
+	var a []keyed = []keyed{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}}
+	var b []keyed = []keyed{{1, "a"}, {3, "changed"}, {4, "d"}, {5, "e"}}
+
+	var delta Delta = DiffByKey(a, b, func(k keyed) int { return k.id })
+	var expected Delta = Delta{Added: []Mark{Mark{3, 4}}, Removed: []Mark{Mark{1, 2}}}
+	if fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+}
+
+func TestModified(t *testing.T) {
+	var a []keyed = []keyed{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}}
+	var b []keyed = []keyed{{1, "a"}, {3, "changed"}, {4, "d"}, {5, "e"}}
+
+	var delta Delta = DiffByKey(a, b, func(k keyed) int { return k.id })
+	var changes KeyedChanges = Modified(a, b, delta, func(x, y keyed) bool { return x == y })
+	if fmt.Sprint(changes.Delta) != fmt.Sprint(delta) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", changes.Delta, delta)
+	}
+	if expected := [][2]int{{0, 0}, {3, 2}}; fmt.Sprint(changes.Unchanged) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected unchanged elements\nGot %v\nExpected %v", changes.Unchanged, expected)
+	}
+	if expected := [][2]int{{2, 1}}; fmt.Sprint(changes.Modified) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected modified elements\nGot %v\nExpected %v", changes.Modified, expected)
+	}
+}
+
+func TestDiffGroups(t *testing.T) {
+	// The id is the group, the value is the payload
+	var a []keyed = []keyed{{1, "apple"}, {1, "pear"}, {2, "carrot"}, {3, "rice"}}
+	var b []keyed = []keyed{{1, "plum"}, {1, "pear"}, {2, "leek"}, {4, "salt"}}
+
+	var delta Delta = DiffGroups(a, b, func(k keyed) int { return k.id })
+	var expected Delta = Delta{Added: []Mark{Mark{3, 4}}, Removed: []Mark{Mark{3, 4}}}
+	if fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+}
+
+func TestDiffProjected(t *testing.T) {
+	type record struct {
+		name  string
+		age   int
+		notes string
+	}
+	type projection struct {
+		name string
+		age  int
+	}
+	var a []record = []record{{"ann", 30, "x"}, {"bob", 40, "y"}, {"cid", 50, "z"}}
+	var b []record = []record{{"ann", 30, "changed"}, {"bob", 41, "y"}, {"cid", 50, "z"}}
+
+	var delta Delta = DiffProjected(a, b, func(r record) projection {
+		return projection{r.name, r.age}
+	})
+	var expected Delta = Delta{Added: []Mark{Mark{1, 2}}, Removed: []Mark{Mark{1, 2}}}
+	if fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+}
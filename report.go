@@ -0,0 +1,27 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+)
+
+// Reports the differences between two slices for a test failure message,
+// in the spirit of cmp.Diff from github.com/google/go-cmp: an empty string
+// when the slices are equal, otherwise a unified diff of their elements,
+// formatted with %v, with removed elements prefixed by "-" and added ones
+// by "+". As in cmp.Diff the first slice is usually the expected one.
+//
+// The package does not depend on go-cmp, so this is not a cmp.Reporter.
+// It is meant for custom slice types whose differences cmp.Diff reports
+// element by element, e.g. from a cmp.Comparer or directly in a test:
+//
+//	if report := diff.ReportSlices(want, got, equal); report != "" {
+//		t.Errorf("mismatch (-want +got):\n%s", report)
+//	}
+func ReportSlices[T any](x, y []T, equal func(T, T) bool) string {
+	var delta Delta = Diff(WithEqual(len(x), len(y), func(i, j int) bool {
+		return equal(x[i], y[j])
+	}))
+	return FormatUnifiedFunc(x, y, delta, 3, func(element T) string {
+		return fmt.Sprintf("%v", element)
+	})
+}
@@ -0,0 +1,20 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"testing"
+)
+
+func TestReportSlices(t *testing.T) {
+	type point struct{ x, y int }
+	var equal = func(a, b point) bool { return a == b }
+	var want []point = []point{{0, 0}, {1, 1}, {2, 2}}
+	var got []point = []point{{0, 0}, {1, 2}, {2, 2}, {3, 3}}
+
+	if report := ReportSlices(want, want, equal); report != "" {
+		t.Errorf("Expected an empty report for equal slices, got\n%s", report)
+	}
+	var expected string = "@@ -1,3 +1,4 @@\n {0 0}\n-{1 1}\n+{1 2}\n {2 2}\n+{3 3}\n"
+	if report := ReportSlices(want, got, equal); report != expected {
+		t.Errorf("Unexpected report\nGot\n%s\nExpected\n%s", report, expected)
+	}
+}
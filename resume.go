@@ -0,0 +1,91 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrCheckpoint is returned when a checkpoint is malformed
+// or was taken for sequences of different lengths
+var ErrCheckpoint = errors.New("diff: invalid checkpoint")
+
+// Diffs the provided data in resumable steps. Each call compares the
+// elements of up to rows rows of the match matrix, starting from where
+// the checkpoint left off (a nil checkpoint starts from scratch). Until
+// the matrix is complete an empty delta is returned together with the
+// checkpoint to resume from. Once it is complete the recursion runs
+// and the final delta is returned with a nil checkpoint.
+//
+// The checkpoint holds the sequences' lengths and the filled rows of the
+// matrix, which is where the O(n*m) cost of the algorithm lies. The recursion
+// itself is not checkpointed as it only reads the matrix.
+// A rows value of zero or less fills the remaining matrix in one go.
+func DiffResumable(data Interface, checkpoint []byte, rows int) (Delta, []byte, error) {
+	var len1, len2 = data.Len()
+	var mx *matrix = Config{}.matrix(len1, len2)
+
+	var filled int
+	if checkpoint != nil {
+		var err error
+		if filled, err = mx.restore(checkpoint); err != nil {
+			return Delta{}, nil, err
+		}
+	}
+
+	var to int = len1
+	if rows > 0 && filled+rows < len1 {
+		to = filled + rows
+	}
+	mx.fill(data, filled, to)
+	if to < len1 {
+		return Delta{}, mx.checkpoint(to), nil
+	}
+	return mx.recursiveDiff(box{point{0, 0}, len1, len2}, 1), nil, nil
+}
+
+// Serializes the dimensions and the first rows of the matrix
+func (mx *matrix) checkpoint(rows int) []byte {
+	var result []byte = make([]byte, 3*binary.MaxVarintLen64, 3*binary.MaxVarintLen64+(rows*mx.lenY+7)/8)
+	var n int = binary.PutUvarint(result, uint64(mx.lenX))
+	n += binary.PutUvarint(result[n:], uint64(mx.lenY))
+	n += binary.PutUvarint(result[n:], uint64(rows))
+	result = result[:n]
+
+	var current byte
+	for z := 0; z < rows*mx.lenY; z++ {
+		if mx.v.Peek(uint(z)) {
+			current |= 1 << uint(z%8)
+		}
+		if z%8 == 7 {
+			result, current = append(result, current), 0
+		}
+	}
+	if (rows*mx.lenY)%8 != 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// Restores the rows of the matrix from a checkpoint and returns their count
+func (mx *matrix) restore(checkpoint []byte) (int, error) {
+	var header [3]uint64
+	for i := range header {
+		value, n := binary.Uvarint(checkpoint)
+		if n <= 0 {
+			return 0, ErrCheckpoint
+		}
+		header[i], checkpoint = value, checkpoint[n:]
+	}
+	if header[0] != uint64(mx.lenX) || header[1] != uint64(mx.lenY) || header[2] > header[0] {
+		return 0, ErrCheckpoint
+	}
+
+	var rows int = int(header[2])
+	if len(checkpoint) != (rows*mx.lenY+7)/8 {
+		return 0, ErrCheckpoint
+	}
+	for z := 0; z < rows*mx.lenY; z++ {
+		mx.v.Poke(uint(z), checkpoint[z/8]&(1<<uint(z%8)) != 0)
+	}
+	return rows, nil
+}
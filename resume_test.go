@@ -0,0 +1,42 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffResumable(t *testing.T) {
+	var seq1, seq2 string = "abcdefghijklmnop", "abbcedfhijXlmnoqp"
+	var calls int
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		calls++
+		return seq1[i] == seq2[j]
+	})
+
+	delta, checkpoint, err := DiffResumable(data, nil, len(seq1)/2)
+	if err != nil || checkpoint == nil || len(delta.Added)+len(delta.Removed) != 0 {
+		t.Fatalf("Expected a checkpoint after the first half, got %v %v %v", delta, checkpoint, err)
+	}
+	if calls != len(seq1)/2*len(seq2) {
+		t.Errorf("Expected half of the comparisons, got %d", calls)
+	}
+
+	delta, checkpoint, err = DiffResumable(data, checkpoint, len(seq1))
+	if err != nil || checkpoint != nil {
+		t.Fatalf("Expected a final delta, got %v %v", checkpoint, err)
+	}
+	if calls != len(seq1)*len(seq2) {
+		t.Errorf("Expected each pair to be compared once, got %d comparisons", calls)
+	}
+	if fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", Diff(data)) {
+		t.Errorf("Expected the resumed delta to match a single run, got %v", delta)
+	}
+
+	_, checkpoint, _ = DiffResumable(data, nil, 1)
+	if _, _, err = DiffResumable(WithEqual(1, 1, nil), checkpoint, 0); err != ErrCheckpoint {
+		t.Errorf("Expected an error for a checkpoint of different sequences, got %v", err)
+	}
+	if _, _, err = DiffResumable(data, checkpoint[:len(checkpoint)-1], 0); err != ErrCheckpoint {
+		t.Errorf("Expected an error for a truncated checkpoint, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+)
+
+// Returns the Jaccard similarity of the sets of k-gram shingles
+// of the two token sequences, ranging from 0 (no shared shingles)
+// to 1 (identical shingle sets). Unlike an alignment this ignores
+// the order of the shingles, which makes it suitable for detecting
+// near-duplicate documents with moved passages. Sequences shorter
+// than k contribute a single shingle made of all of their tokens.
+func ShingleSimilarity(a, b []string, k int) float64 {
+	if k < 1 {
+		k = 1
+	}
+	var set1, set2 map[string]struct{} = shingles(a, k), shingles(b, k)
+	if len(set1) == 0 && len(set2) == 0 {
+		return 1
+	}
+
+	var common int
+	for s := range set1 {
+		if _, found := set2[s]; found {
+			common++
+		}
+	}
+	return float64(common) / float64(len(set1)+len(set2)-common)
+}
+
+// Returns the set of k-gram shingles of the token sequence
+func shingles(tokens []string, k int) map[string]struct{} {
+	var result map[string]struct{} = make(map[string]struct{})
+	if len(tokens) == 0 {
+		return result
+	}
+	if len(tokens) < k {
+		k = len(tokens)
+	}
+	for i := 0; i+k <= len(tokens); i++ {
+		// Tokens are joined with a separator that is unlikely
+		// to appear in them so that ("ab", "c") and ("a", "bc") differ
+		result[strings.Join(tokens[i:i+k], "\x00")] = struct{}{}
+	}
+	return result
+}
+
+// Returns the similarity ratio of the two sequences, computed as
+// twice the number of matched elements over the total number of elements.
+// It ranges from 0 (nothing in common) to 1 (equal sequences).
+func Ratio(data Interface) float64 {
+	var len1, len2 = data.Len()
+	return ratio(Diff(data), len1, len2)
+}
+
+// Returns the similarity ratio implied by a delta between sequences of the given lengths
+func ratio(d Delta, len1, len2 int) float64 {
+	if len1+len2 == 0 {
+		return 1
+	}
+	var removed, _ = d.Len()
+	return 2 * float64(len1-removed) / float64(len1+len2)
+}
+
+// Diffs two sequences given a precomputed similarity matrix, where
+// sim[i][j] rates the i-th element of the first sequence against
+// the j-th element of the second. Elements whose similarity reaches
+// the threshold are treated as equal, which allows for any similarity
+// function, including expensive ones, to be computed once upfront.
+// The matrix must be rectangular, the length of the second sequence
+// is taken from its first row, so an empty matrix diffs two empty sequences.
+func DiffSimilarityMatrix(sim [][]float64, threshold float64) Delta {
+	var len1, len2 int = len(sim), 0
+	if len1 > 0 {
+		len2 = len(sim[0])
+	}
+	return Diff(WithEqual(len1, len2, func(i, j int) bool {
+		return sim[i][j] >= threshold
+	}))
+}
+
+// Returns the similarity of the multisets of q-grams, i.e. of the runs
+// of q consecutive elements, of the two sequences as identified by hash,
+// ranging from 0 (no shared q-grams) to 1 (identical multisets). Shared
+// q-grams are counted as often as they occur in both, relative to the
+// average number of q-grams per sequence.
+//
+// This takes linear time and is meant as a cheap prefilter before Diff.
+// It approximates how much of the sequences an alignment would keep but
+// ignores the order of the q-grams, so reordered content looks similar,
+// and hash collisions make different q-grams look the same. A small q is
+// lenient, as short q-grams are easily shared by unrelated content, while a
+// large q is strict, as each change spoils every q-gram that overlaps it.
+// Sequences shorter than q contribute a single q-gram of all of their elements.
+func QGramSimilarity[T any](a, b []T, q int, hash func([]T) uint64) float64 {
+	if q < 1 {
+		q = 1
+	}
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	var counts map[uint64]int = make(map[uint64]int)
+	var total int
+	for _, g := range qgrams(a, q, hash) {
+		counts[g]++
+		total++
+	}
+	var common int
+	for _, g := range qgrams(b, q, hash) {
+		if counts[g] > 0 {
+			counts[g]--
+			common++
+		}
+		total++
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+// Returns the hashes of the q-grams of the sequence
+func qgrams[T any](sequence []T, q int, hash func([]T) uint64) []uint64 {
+	if len(sequence) == 0 {
+		return nil
+	}
+	if len(sequence) < q {
+		q = len(sequence)
+	}
+	var result []uint64 = make([]uint64, 0, len(sequence)-q+1)
+	for i := 0; i+q <= len(sequence); i++ {
+		result = append(result, hash(sequence[i:i+q]))
+	}
+	return result
+}
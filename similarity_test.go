@@ -0,0 +1,86 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestShingleSimilarity(t *testing.T) {
+	var original []string = strings.Fields("the quick brown fox jumps over the lazy dog and runs into the forest")
+	var copied []string = strings.Fields("the quick brown fox jumps over the lazy dog and runs into the woods")
+	var unrelated []string = strings.Fields("a completely different sentence about compilers and their optimizations")
+
+	if s := ShingleSimilarity(original, copied, 3); s < 0.8 {
+		t.Errorf("Expected a high similarity for a near-duplicate, got %f", s)
+	}
+	if s := ShingleSimilarity(original, unrelated, 3); s > 0.1 {
+		t.Errorf("Expected a near zero similarity for unrelated documents, got %f", s)
+	}
+	if s := ShingleSimilarity(original, original, 3); s != 1 {
+		t.Errorf("Expected a similarity of 1 for identical documents, got %f", s)
+	}
+	if s := ShingleSimilarity(nil, nil, 3); s != 1 {
+		t.Errorf("Expected a similarity of 1 for empty documents, got %f", s)
+	}
+	if s := ShingleSimilarity([]string{"ab", "c"}, []string{"a", "bc"}, 2); s != 0 {
+		t.Errorf("Expected tokens to be kept apart, got %f", s)
+	}
+}
+
+func TestDiffSimilarityMatrix(t *testing.T) {
+	var sim [][]float64 = [][]float64{
+		{0.9, 0.1, 0.2, 0.0},
+		{0.3, 0.7, 0.95, 0.1},
+		{0.0, 0.2, 0.4, 0.85},
+	}
+	var expected Delta = Delta{Added: []Mark{Mark{1, 2}}}
+	if delta := DiffSimilarityMatrix(sim, 0.8); fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta\nGot %v\nExpected %v", delta, expected)
+	}
+
+	// Lowering the threshold aligns the second element with the second column
+	expected = Delta{Added: []Mark{Mark{2, 3}}}
+	if delta := DiffSimilarityMatrix(sim, 0.7); fmt.Sprint(delta) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected delta for a lower threshold\nGot %v\nExpected %v", delta, expected)
+	}
+
+	if delta := DiffSimilarityMatrix(nil, 0.5); len(delta.Added) != 0 || len(delta.Removed) != 0 {
+		t.Errorf("Expected an empty delta for an empty matrix, got %v", delta)
+	}
+}
+
+func TestQGramSimilarity(t *testing.T) {
+	var hash = func(gram []byte) uint64 {
+		var h = fnv.New64a()
+		h.Write(gram)
+		return h.Sum64()
+	}
+	data := []struct {
+		a, b     string
+		q        int
+		expected float64
+	}{
+		{"", "", 2, 1},
+		{"abc", "", 2, 0},
+		{"abcd", "abcd", 2, 1},
+		// ab bc cd against ab bx xd
+		{"abcd", "abxd", 2, 1.0 / 3},
+		// Every 3-gram overlaps the change
+		{"abcd", "abxd", 3, 0},
+		{"abcd", "abxd", 1, 0.75},
+		// Duplicates are matched once each
+		{"aaaa", "aa", 2, 2.0 / 4},
+		// Order is ignored
+		{"abcabc", "bcabca", 3, 2 * 3.0 / 8},
+		{"ab", "ab", 5, 1},
+	}
+
+	for _, testCase := range data {
+		if s := QGramSimilarity([]byte(testCase.a), []byte(testCase.b), testCase.q, hash); math.Abs(s-testCase.expected) > 1e-9 {
+			t.Errorf("Unexpected similarity of [%s] [%s] for q=%d\nGot %f\nExpected %f", testCase.a, testCase.b, testCase.q, s, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Normalizes the placement of the delta's pure insertions and deletions:
+// when a changed run could equally be placed further down, e.g. a blank
+// line inserted next to another blank line, it is slid down as far as
+// the following unchanged elements allow. Runs are only ever slid down
+// and no indentation heuristic is applied, unlike git's compaction.
+// This makes deltas of similar edits look alike and tends to end
+// insertions of whole blocks at their natural boundary. Regions that
+// both remove and add elements are left in place. Go methods cannot
+// declare type parameters, hence the delta is passed explicitly.
+func SlideBoundaries[T any](d Delta, a, b []T, equal func(T, T) bool) Delta {
+	var changes []box = d.changes()
+	for i := range changes {
+		var c *box = &changes[i]
+		// The unchanged run after the region ends at the next region
+		var endX int = len(a)
+		if i+1 < len(changes) {
+			endX = changes[i+1].x
+		}
+		for c.lenX < endX {
+			// The first changed element swaps places with the kept one after the region
+			var insertion bool = c.x == c.lenX && c.y < c.lenY && equal(a[c.lenX], b[c.y])
+			var deletion bool = c.y == c.lenY && c.x < c.lenX && equal(a[c.x], b[c.lenY])
+			if !insertion && !deletion {
+				break
+			}
+			c.x, c.y, c.lenX, c.lenY = c.x+1, c.y+1, c.lenX+1, c.lenY+1
+		}
+	}
+
+	var result Delta
+	for _, c := range changes {
+		if c.lenX > c.x {
+			result.Removed = appendMark(result.Removed, c.x, c.lenX)
+		}
+		if c.lenY > c.y {
+			result.Added = appendMark(result.Added, c.y, c.lenY)
+		}
+	}
+	return result
+}
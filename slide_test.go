@@ -0,0 +1,55 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSlideBoundaries(t *testing.T) {
+	var equal = func(x, y string) bool { return x == y }
+	data := []struct {
+		a, b  []string
+		delta Delta
+		slid  Delta
+	}{
+		// A block with a trailing blank line inserted after a blank line,
+		// which git shows as "+b" followed by "+" rather than the other way around
+		{
+			[]string{"a", "", "c"},
+			[]string{"a", "", "b", "", "c"},
+			Delta{Added: []Mark{Mark{1, 3}}},
+			Delta{Added: []Mark{Mark{2, 4}}},
+		},
+		// A deletion within a run of equal elements
+		{
+			[]string{"x", "y", "y", "y", "z"},
+			[]string{"x", "y", "y", "z"},
+			Delta{Removed: []Mark{Mark{1, 2}}},
+			Delta{Removed: []Mark{Mark{3, 4}}},
+		},
+		// Replacements stay in place
+		{
+			[]string{"a", "b", "a"},
+			[]string{"a", "c", "a"},
+			Delta{Added: []Mark{Mark{1, 2}}, Removed: []Mark{Mark{1, 2}}},
+			Delta{Added: []Mark{Mark{1, 2}}, Removed: []Mark{Mark{1, 2}}},
+		},
+		// Insertions that cannot slide stay in place
+		{
+			[]string{"a", "c"},
+			[]string{"a", "b", "c"},
+			Delta{Added: []Mark{Mark{1, 2}}},
+			Delta{Added: []Mark{Mark{1, 2}}},
+		},
+	}
+
+	for _, testCase := range data {
+		var slid Delta = SlideBoundaries(testCase.delta, testCase.a, testCase.b, equal)
+		if fmt.Sprintf("%v", slid) != fmt.Sprintf("%v", testCase.slid) {
+			t.Errorf("Unexpected delta for %q and %q\nGot %v\nExpected %v", testCase.a, testCase.b, slid, testCase.slid)
+		}
+		if !EquivalentTransforms(testCase.a, testCase.b, testCase.delta, slid) {
+			t.Errorf("Expected the slid delta %v to remain correct", slid)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package diff // import "github.com/spaskalev/diff"
+
+// A StreamDiffer diffs two byte streams that are too long to be held in
+// memory. The streams are buffered and diffed in windows of a fixed size
+// that overlap by a number of bytes. Changes are confirmed up to the last
+// aligned position before the overlap, after which the next window starts.
+// Memory use is bounded by the window size: once either stream has a whole
+// window buffered, that window is diffed against whatever the other stream
+// has buffered so far, even if this is less or nothing at all. Streams
+// that are written far apart are thus reported as replaced.
+//
+// The result is approximate near window boundaries: a change that spans
+// more than the overlap may be reported as a larger removal and addition,
+// and if a window holds no aligned position before its overlap both
+// streams are treated as replaced up to that point.
+type StreamDiffer struct {
+	window, overlap int
+	a, b            []byte
+	offsetA         int
+	offsetB         int
+	delta           Delta
+}
+
+// Returns a StreamDiffer with the given window size and overlap in bytes
+func NewStreamDiffer(window, overlap int) *StreamDiffer {
+	if window < 1 {
+		window = 1
+	}
+	if overlap < 0 || overlap >= window {
+		overlap = window / 2
+	}
+	return &StreamDiffer{window: window, overlap: overlap}
+}
+
+// Appends the next chunks of the first and the second stream, either of
+// which may be empty, and diffs windows until neither stream has a whole
+// one buffered
+func (s *StreamDiffer) Write(a, b []byte) {
+	s.a, s.b = append(s.a, a...), append(s.b, b...)
+	for len(s.a) >= s.window || len(s.b) >= s.window {
+		s.advance(s.window-s.overlap, false)
+	}
+}
+
+// Diffs and confirms the rest of both streams once they have ended
+func (s *StreamDiffer) Close() {
+	s.advance(max(len(s.a), len(s.b)), true)
+}
+
+// Returns the changes confirmed so far, with marks
+// referencing absolute offsets within the streams
+func (s *StreamDiffer) Changes() Delta {
+	return s.delta
+}
+
+// Diffs the current window and confirms its changes up to the furthest
+// aligned position that does not exceed limit in either stream.
+// The final window is diffed and confirmed as a whole.
+func (s *StreamDiffer) advance(limit int, final bool) {
+	var a, b []byte = s.a, s.b
+	if !final {
+		a, b = a[:min(s.window, len(a))], b[:min(s.window, len(b))]
+	}
+	var delta Delta = Diff(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}))
+
+	// Find the furthest aligned position, which lies within an unchanged run
+	var changes []box = delta.changes()
+	var cut point
+	var previous point
+	for i := 0; i <= len(changes); i++ {
+		var next point = point{len(a), len(b)}
+		if i < len(changes) {
+			next = changes[i].point
+		}
+		var k int = min(next.x-previous.x, min(limit-previous.x, limit-previous.y))
+		if k >= 0 {
+			cut = point{previous.x + k, previous.y + k}
+		}
+		if i < len(changes) {
+			previous = point{changes[i].lenX, changes[i].lenY}
+		}
+	}
+
+	if final {
+		cut = point{len(a), len(b)}
+	} else if cut.x == 0 && cut.y == 0 {
+		// No alignment within the limit, the leading parts are replaced
+		cut = point{min(limit, len(a)), min(limit, len(b))}
+		changes = []box{box{point{0, 0}, cut.x, cut.y}}
+	}
+	for _, c := range changes {
+		if c.x < cut.x || c.y < cut.y {
+			if c.lenX > c.x {
+				s.delta.Removed = appendMark(s.delta.Removed, s.offsetA+c.x, s.offsetA+min(c.lenX, cut.x))
+			}
+			if c.lenY > c.y {
+				s.delta.Added = appendMark(s.delta.Added, s.offsetB+c.y, s.offsetB+min(c.lenY, cut.y))
+			}
+		}
+	}
+	s.a, s.b = s.a[cut.x:], s.b[cut.y:]
+	s.offsetA, s.offsetB = s.offsetA+cut.x, s.offsetB+cut.y
+}
@@ -0,0 +1,62 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestStreamDiffer(t *testing.T) {
+	var a []byte = bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog. "), 20)
+	var b []byte = append([]byte(nil), a...)
+	copy(b[100:], "QUICK")
+	b = append(b[:500], append([]byte("an inserted sentence. "), b[500:]...)...)
+	b = append(b[:800], b[810:]...)
+
+	var streamer *StreamDiffer = NewStreamDiffer(64, 16)
+	for i := 0; i < len(a) || i < len(b); i += 10 {
+		streamer.Write(a[min(i, len(a)):min(i+10, len(a))], b[min(i, len(b)):min(i+10, len(b))])
+	}
+	streamer.Close()
+
+	var changes Delta = streamer.Changes()
+	var data Interface = WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	})
+	if err := Verify(data, changes, nil); err != nil {
+		t.Fatalf("Unexpected invalid delta %v: %v", changes, err)
+	}
+	var expected Delta = Delta{
+		Added:   []Mark{Mark{100, 105}, Mark{500, 522}},
+		Removed: []Mark{Mark{100, 105}, Mark{778, 788}},
+	}
+	if fmt.Sprintf("%v", changes) != fmt.Sprintf("%v", expected) {
+		t.Errorf("Unexpected changes\nGot %v\nExpected %v", changes, expected)
+	}
+
+	var empty *StreamDiffer = NewStreamDiffer(8, 2)
+	empty.Write(nil, []byte("abc"))
+	empty.Close()
+	if changes := empty.Changes(); fmt.Sprintf("%v", changes) != "{[{0 3}] []}" {
+		t.Errorf("Unexpected changes against an empty stream %v", changes)
+	}
+}
+
+func TestStreamDifferBounded(t *testing.T) {
+	var a, b []byte = bytes.Repeat([]byte("abcdefgh"), 1000), bytes.Repeat([]byte("abcdXfgh"), 10)
+
+	var streamer *StreamDiffer = NewStreamDiffer(64, 16)
+	streamer.Write(a, nil)
+	if len(streamer.a) >= 64 || len(streamer.b) >= 64 {
+		t.Errorf("Unexpected buffered bytes past the window\nGot %d and %d", len(streamer.a), len(streamer.b))
+	}
+	streamer.Write(nil, b)
+	streamer.Close()
+
+	var changes Delta = streamer.Changes()
+	if err := Verify(WithEqual(len(a), len(b), func(i, j int) bool {
+		return a[i] == b[j]
+	}), changes, nil); err != nil {
+		t.Errorf("Unexpected invalid delta %v: %v", changes, err)
+	}
+}
@@ -0,0 +1,70 @@
+package diff // import "github.com/spaskalev/diff"
+
+// Diffs the provided data in linear time when the second sequence
+// is a subsequence of the first one, i.e. when it can be obtained
+// by removals only. Each element of the second sequence is matched
+// to its earliest possible occurrence in the first. Returns false
+// if the second sequence is not a subsequence of the first,
+// in which case the full Diff should be used instead.
+func DiffSubsequence(data Interface) (Delta, bool) {
+	var len1, len2 = data.Len()
+	var result Delta
+	var i int
+	for j := 0; j < len2; j++ {
+		var from int = i
+		for i < len1 && !data.Equal(i, j) {
+			i++
+		}
+		if i == len1 {
+			return Delta{}, false
+		}
+		if i > from {
+			result.Removed = append(result.Removed, Mark{from, i})
+		}
+		i++
+	}
+	if i < len1 {
+		result.Removed = append(result.Removed, Mark{i, len1})
+	}
+	return result, true
+}
+
+// Reports whether the second sequence extends the first one at its end,
+// i.e. whether the diff consists of a single trailing addition at most,
+// and returns that delta. The check stops at the first differing element
+// and compares no more than the length of the first sequence. Returns
+// false and an empty delta otherwise, in which case the full Diff
+// should be used to find the changes.
+func IsPrefixExtension(data Interface) (bool, Delta) {
+	var len1, len2 = data.Len()
+	if len2 < len1 {
+		return false, Delta{}
+	}
+	for i := 0; i < len1; i++ {
+		if !data.Equal(i, i) {
+			return false, Delta{}
+		}
+	}
+	var result Delta
+	if len2 > len1 {
+		result.Added = []Mark{Mark{len1, len2}}
+	}
+	return true, result
+}
+
+// Returns the number of leading and trailing elements that are equal in both
+// sequences, comparing them from both ends in linear time. The suffix is
+// counted among the elements that follow the prefix, so the two never
+// overlap: when one sequence is a prefix of the other its whole length
+// is the prefix and the suffix is zero.
+func CommonAffixes(data Interface) (prefix, suffix int) {
+	var len1, len2 = data.Len()
+	var shorter int = min(len1, len2)
+	for prefix < shorter && data.Equal(prefix, prefix) {
+		prefix++
+	}
+	for suffix < shorter-prefix && data.Equal(len1-1-suffix, len2-1-suffix) {
+		suffix++
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffSubsequence(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		delta      Delta
+		ok         bool
+	}{
+		{"", "", Delta{}, true},
+		{"abc", "", Delta{Removed: []Mark{Mark{0, 3}}}, true},
+		{"abc", "abc", Delta{}, true},
+		{"abcdef", "bdf", Delta{Removed: []Mark{Mark{0, 1}, Mark{2, 3}, Mark{4, 5}}}, true},
+		{"abcdef", "abef", Delta{Removed: []Mark{Mark{2, 4}}}, true},
+		{"abcdef", "ab", Delta{Removed: []Mark{Mark{2, 6}}}, true},
+		{"", "a", Delta{}, false},
+		{"abc", "ba", Delta{}, false},
+		{"abc", "abcd", Delta{}, false},
+	}
+
+	for _, testCase := range data {
+		delta, ok := DiffSubsequence(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}))
+
+		if ok != testCase.ok || fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected result for data\n[%s]\n[%s]\nGot %v %v\nExpected %v %v",
+				testCase.seq1, testCase.seq2, delta, ok, testCase.delta, testCase.ok)
+		}
+	}
+}
+
+func TestIsPrefixExtension(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		ok         bool
+		delta      Delta
+	}{
+		{"", "", true, Delta{}},
+		{"", "ab", true, Delta{Added: []Mark{Mark{0, 2}}}},
+		{"abc", "abc", true, Delta{}},
+		{"abc", "abcde", true, Delta{Added: []Mark{Mark{3, 5}}}},
+		{"abc", "ab", false, Delta{}},
+		{"abc", "axbc", false, Delta{}},
+		{"abc", "abd", false, Delta{}},
+		{"abc", "xabc", false, Delta{}},
+	}
+
+	for _, testCase := range data {
+		ok, delta := IsPrefixExtension(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}))
+
+		if ok != testCase.ok || fmt.Sprintf("%v", delta) != fmt.Sprintf("%v", testCase.delta) {
+			t.Errorf("Unexpected result for data\n[%s]\n[%s]\nGot %v %v\nExpected %v %v",
+				testCase.seq1, testCase.seq2, ok, delta, testCase.ok, testCase.delta)
+		}
+	}
+}
+
+func TestCommonAffixes(t *testing.T) {
+	data := []struct {
+		seq1, seq2     string
+		prefix, suffix int
+	}{
+		{"", "", 0, 0},
+		{"abc", "", 0, 0},
+		{"abc", "abc", 3, 0},
+		{"abc", "abcd", 3, 0},
+		{"bcd", "abcd", 0, 3},
+		{"abXcd", "abYYcd", 2, 2},
+		{"aa", "aaa", 2, 0},
+		{"abc", "xyz", 0, 0},
+	}
+
+	for _, testCase := range data {
+		prefix, suffix := CommonAffixes(WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		}))
+		if prefix != testCase.prefix || suffix != testCase.suffix {
+			t.Errorf("Unexpected affixes for data\n[%s]\n[%s]\nGot %d %d\nExpected %d %d",
+				testCase.seq1, testCase.seq2, prefix, suffix, testCase.prefix, testCase.suffix)
+		}
+	}
+}
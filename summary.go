@@ -0,0 +1,20 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+)
+
+// The number of unchanged elements around the changes of a summarized
+// diff's hunks, as in the conventional unified diff
+const summaryContext = 3
+
+// Diffs the provided data and returns a one-line summary of the result
+// in the form "5 removed, 3 added, 2 hunks; 88% similar", where the hunks
+// are counted as FormatUnified and HunkCount do with a context of 3
+func Summarize(data Interface) string {
+	var len1, len2 = data.Len()
+	var delta Delta = Diff(data)
+	var removed, added = delta.Len()
+	return fmt.Sprintf("%d removed, %d added, %d hunks; %.0f%% similar",
+		removed, added, len(delta.hunks(len1, len2, summaryContext)), 100*ratio(delta, len1, len2))
+}
@@ -0,0 +1,39 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		summary    string
+	}{
+		{"", "", "0 removed, 0 added, 0 hunks; 100% similar"},
+		{"abc", "abc", "0 removed, 0 added, 0 hunks; 100% similar"},
+		{"abc", "", "3 removed, 0 added, 1 hunks; 0% similar"},
+		{"abcdefgh", "abbcedfh", "2 removed, 2 added, 1 hunks; 75% similar"},
+		{"abcdefgh", "abXdefYZ", "3 removed, 3 added, 1 hunks; 62% similar"},
+		// Changes more than twice the context apart are separate hunks
+		{"aXbcdefghYi", "abcdefghi", "2 removed, 0 added, 2 hunks; 90% similar"},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		summary := Summarize(input)
+
+		if summary != testCase.summary {
+			t.Errorf("Unexpected summary for data\n[%s]\n[%s]\nGot %s\nExpected %s",
+				testCase.seq1, testCase.seq2, summary, testCase.summary)
+		}
+		// The hunks are those of a unified diff
+		if hunks := HunkCount(input, summaryContext); !strings.Contains(summary, fmt.Sprintf(", %d hunks;", hunks)) {
+			t.Errorf("Unexpected hunk count in %s for data\n[%s]\n[%s]\nExpected %d",
+				summary, testCase.seq1, testCase.seq2, hunks)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"time"
+)
+
+// A Telemetry struct reports where the time of a diff went
+type Telemetry struct {
+	// Time spent comparing elements upfront to fill the match matrix
+	FillTime time.Duration
+	// Time spent recursing over the match matrix
+	RecursionTime time.Duration
+	// Number of calls to the data's Equal method
+	EqualCalls int
+	// Number of recursive calls, see Stats
+	Calls int
+	// Number of marks in the largest delta held at once. As partial
+	// deltas are only ever concatenated, this is the size of the result.
+	PeakMarks int
+}
+
+// Diffs the provided data like Diff and reports telemetry about the diff.
+// The instrumentation is limited to this function, so Diff is not slowed
+// down by it, although measuring it here adds a little overhead of its own.
+func DiffTimed(data Interface) (Delta, Telemetry) {
+	var telemetry Telemetry
+	var len1, len2 = data.Len()
+	var counted Interface = WithEqual(len1, len2, func(i, j int) bool {
+		telemetry.EqualCalls++
+		return data.Equal(i, j)
+	})
+
+	var start time.Time = time.Now()
+	var mx *matrix = Config{}.matrix(len1, len2)
+	mx.fill(counted, 0, len1)
+	telemetry.FillTime = time.Since(start)
+
+	start = time.Now()
+	var delta Delta = mx.recursiveDiff(box{point{0, 0}, len1, len2}, 1)
+	telemetry.RecursionTime = time.Since(start)
+	telemetry.Calls = mx.stats.Calls
+	telemetry.PeakMarks = len(delta.Removed) + len(delta.Added)
+	return delta, telemetry
+}
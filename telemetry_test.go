@@ -0,0 +1,36 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffTimed(t *testing.T) {
+	data := []struct {
+		seq1, seq2 string
+		equalCalls int
+	}{
+		{"abcdefgh", "abcdXfgh", 8 * 8},
+		{"abcdefgh", "abbcedfh", 8 * 8},
+		{"abc", "abcd", 3 * 4},
+	}
+
+	for _, testCase := range data {
+		var input Interface = WithEqual(len(testCase.seq1), len(testCase.seq2), func(i, j int) bool {
+			return testCase.seq1[i] == testCase.seq2[j]
+		})
+		delta, telemetry := DiffTimed(input)
+		if expected := Diff(input); fmt.Sprint(delta) != fmt.Sprint(expected) {
+			t.Errorf("Unexpected delta for [%s] [%s]\nGot %v\nExpected %v", testCase.seq1, testCase.seq2, delta, expected)
+		}
+		if telemetry.EqualCalls != testCase.equalCalls {
+			t.Errorf("Unexpected number of comparisons for [%s] [%s]\nGot %d\nExpected %d", testCase.seq1, testCase.seq2, telemetry.EqualCalls, testCase.equalCalls)
+		}
+		if telemetry.PeakMarks != len(delta.Removed)+len(delta.Added) {
+			t.Errorf("Unexpected peak mark count %d for %v", telemetry.PeakMarks, delta)
+		}
+		if _, stats := (Config{}).DiffStats(input); telemetry.Calls != stats.Calls {
+			t.Errorf("Unexpected number of calls\nGot %d\nExpected %d", telemetry.Calls, stats.Calls)
+		}
+	}
+}
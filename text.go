@@ -0,0 +1,134 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Returns the smallest byte range that differs between the two strings:
+// a[start:oldEnd] was replaced by b[start:newEnd]. The range is found by
+// trimming the common prefix and suffix rune by rune, so it never splits
+// a multi-byte rune. Equal strings yield an empty range at their end.
+func DirtyRange(a, b string) (start, oldEnd, newEnd int) {
+	for start < len(a) && start < len(b) {
+		// Invalid bytes decode as the same error rune, so the bytes are compared
+		var _, size1 = utf8.DecodeRuneInString(a[start:])
+		var _, size2 = utf8.DecodeRuneInString(b[start:])
+		if a[start:start+size1] != b[start:start+size2] {
+			break
+		}
+		start += size1
+	}
+
+	oldEnd, newEnd = len(a), len(b)
+	for oldEnd > start && newEnd > start {
+		var _, size1 = utf8.DecodeLastRuneInString(a[start:oldEnd])
+		var _, size2 = utf8.DecodeLastRuneInString(b[start:newEnd])
+		if a[oldEnd-size1:oldEnd] != b[newEnd-size2:newEnd] {
+			break
+		}
+		oldEnd, newEnd = oldEnd-size1, newEnd-size2
+	}
+	return
+}
+
+// An InlineFormat struct holds the delimiters that mark a change in a single
+// line diff: Open precedes the removed text, Separator follows it and
+// precedes the added text, Close follows the added text
+type InlineFormat struct {
+	Open, Separator, Close string
+}
+
+// Returns a single line diff of the two strings with the changes inline,
+// e.g. "foo[bar->baz]qux", as formatted by InlineFormat{"[", "->", "]"}
+func InlineString(a, b string) string {
+	return InlineFormat{"[", "->", "]"}.Format(a, b)
+}
+
+// Returns a single line diff of the two strings, diffed rune by rune, that
+// keeps unchanged text as is and wraps each change in the delimiters.
+// Occurrences of the delimiters and of the backslash in the text
+// are escaped with a backslash so that the result is unambiguous.
+func (f InlineFormat) Format(a, b string) string {
+	var r1, r2 []rune = []rune(a), []rune(b)
+	var delta Delta = Diff(WithEqual(len(r1), len(r2), func(i, j int) bool {
+		return r1[i] == r2[j]
+	}))
+
+	var pairs []string = []string{`\`, `\\`}
+	for _, delimiter := range []string{f.Open, f.Separator, f.Close} {
+		if delimiter != "" {
+			pairs = append(pairs, delimiter, `\`+delimiter)
+		}
+	}
+	var escape *strings.Replacer = strings.NewReplacer(pairs...)
+
+	var builder strings.Builder
+	var x int
+	for _, c := range delta.changes() {
+		builder.WriteString(escape.Replace(string(r1[x:c.x])))
+		builder.WriteString(f.Open)
+		builder.WriteString(escape.Replace(string(r1[c.x:c.lenX])))
+		builder.WriteString(f.Separator)
+		builder.WriteString(escape.Replace(string(r2[c.y:c.lenY])))
+		builder.WriteString(f.Close)
+		x = c.lenX
+	}
+	builder.WriteString(escape.Replace(string(r1[x:])))
+	return builder.String()
+}
+
+// Formats a word diff of the two strings like git diff --word-diff=plain:
+// removed words are wrapped in [-...-] and added ones in {+...+} within
+// the unchanged text. Words are runs of non-space characters. As in git,
+// the unchanged text, including the white space between the words,
+// is taken from the second string.
+func FormatWordDiff(a, b string) string {
+	var words1, words2 [][2]int = words(a), words(b)
+	var delta Delta = Diff(WithEqual(len(words1), len(words2), func(i, j int) bool {
+		return a[words1[i][0]:words1[i][1]] == b[words2[j][0]:words2[j][1]]
+	}))
+
+	var builder strings.Builder
+	var current int
+	for _, c := range delta.changes() {
+		// A removal without additions is placed right after the preceding word
+		var begin, end int
+		if c.lenY > c.y {
+			begin, end = words2[c.y][0], words2[c.lenY-1][1]
+		} else if c.y > 0 {
+			begin, end = words2[c.y-1][1], words2[c.y-1][1]
+		}
+		builder.WriteString(b[current:begin])
+		if c.lenX > c.x {
+			builder.WriteString("[-" + a[words1[c.x][0]:words1[c.lenX-1][1]] + "-]")
+		}
+		if c.lenY > c.y {
+			builder.WriteString("{+" + b[begin:end] + "+}")
+		}
+		current = end
+	}
+	builder.WriteString(b[current:])
+	return builder.String()
+}
+
+// Returns the byte ranges of the runs of non-space characters in the text
+func words(text string) [][2]int {
+	var result [][2]int
+	var start int = -1
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				result = append(result, [2]int{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		result = append(result, [2]int{start, len(text)})
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"testing"
+)
+
+func TestDirtyRange(t *testing.T) {
+	data := []struct {
+		a, b                  string
+		start, oldEnd, newEnd int
+	}{
+		{"", "", 0, 0, 0},
+		{"same", "same", 4, 4, 4},
+		// Interior edits
+		{"hello world", "hello there world", 6, 6, 12},
+		{"hello world", "hello word", 9, 10, 9},
+		{"abcdef", "abXYef", 2, 4, 4},
+		// Pure appends
+		{"abc", "abcdef", 3, 3, 6},
+		{"", "abc", 0, 0, 3},
+		// Pure deletions
+		{"abcdef", "abc", 3, 6, 3},
+		{"abcdef", "def", 0, 3, 0},
+		// Multi-byte runes are never split
+		{"añb", "aéb", 1, 3, 3},
+		{"日本", "日本語", 6, 6, 9},
+		// Invalid bytes are compared as they are
+		{"x\xffy", "x\xfey", 1, 2, 2},
+		{"\xff", "\xff", 1, 1, 1},
+	}
+
+	for _, testCase := range data {
+		start, oldEnd, newEnd := DirtyRange(testCase.a, testCase.b)
+		if start != testCase.start || oldEnd != testCase.oldEnd || newEnd != testCase.newEnd {
+			t.Errorf("Unexpected range for [%s] [%s]\nGot %d %d %d\nExpected %d %d %d",
+				testCase.a, testCase.b, start, oldEnd, newEnd, testCase.start, testCase.oldEnd, testCase.newEnd)
+		}
+	}
+}
+
+func TestInlineString(t *testing.T) {
+	data := []struct {
+		a, b, expected string
+	}{
+		{"", "", ""},
+		{"same", "same", "same"},
+		{"foobarqux", "foobazqux", "fooba[r->z]qux"},
+		{"abc", "abXc", "ab[->X]c"},
+		{"abXc", "abc", "ab[X->]c"},
+		{"añb", "aéb", "a[ñ->é]b"},
+		// Delimiters in the content are escaped
+		{"a[b", "a[c", `a\[[b->c]`},
+		{`a\b`, "a->", `a[\\b->\->]`},
+	}
+
+	for _, testCase := range data {
+		if result := InlineString(testCase.a, testCase.b); result != testCase.expected {
+			t.Errorf("Unexpected inline diff of [%s] [%s]\nGot %s\nExpected %s", testCase.a, testCase.b, result, testCase.expected)
+		}
+	}
+
+	var format InlineFormat = InlineFormat{"{-", "-}{+", "+}"}
+	if result := format.Format("one two", "one six"); result != "one {-two-}{+six+}" {
+		t.Errorf("Unexpected inline diff with custom delimiters\nGot %s\nExpected %s", result, "one {-two-}{+six+}")
+	}
+}
+
+func TestFormatWordDiff(t *testing.T) {
+	// The expected outputs are those of git diff --no-index --word-diff=plain
+	data := []struct {
+		a, b, expected string
+	}{
+		{"", "", ""},
+		{"same text", "same text", "same text"},
+		{"the quick brown fox", "the slow brown fox jumps", "the [-quick-]{+slow+} brown fox {+jumps+}"},
+		{"a b c", "a c", "a[-b-] c"},
+		{"a b", "a  x  b", "a  {+x+}  b"},
+		{"x a b", "a b", "[-x-]a b"},
+		{"a b", "n a b", "{+n+} a b"},
+		{"one two three four", "one 2 3 four", "one [-two three-]{+2 3+} four"},
+	}
+
+	for _, testCase := range data {
+		if result := FormatWordDiff(testCase.a, testCase.b); result != testCase.expected {
+			t.Errorf("Unexpected word diff of [%s] [%s]\nGot %s\nExpected %s", testCase.a, testCase.b, result, testCase.expected)
+		}
+	}
+}
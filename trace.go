@@ -0,0 +1,27 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+	"io"
+)
+
+// A diff.Interface decorator that writes every comparison to a writer
+type trace struct {
+	Interface
+	w io.Writer
+}
+
+// Logs the comparison as "Equal(i, j) = result", one per line
+func (t trace) Equal(i, j int) bool {
+	var result bool = t.Interface.Equal(i, j)
+	fmt.Fprintf(t.w, "Equal(%d, %d) = %t\n", i, j, result)
+	return result
+}
+
+// Returns a diff.Interface implementation that delegates to the inner one
+// and writes each call to Equal along with its result to w, one per line,
+// e.g. to find out why a diff aligned the sequences the way it did.
+// Write errors are ignored so that they do not affect the diff.
+func WithTrace(inner Interface, w io.Writer) Interface {
+	return trace{inner, w}
+}
@@ -0,0 +1,23 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithTrace(t *testing.T) {
+	var seq1, seq2 string = "ab", "b"
+	var log strings.Builder
+	var data Interface = WithTrace(WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	}), &log)
+
+	if len1, len2 := data.Len(); len1 != 2 || len2 != 1 {
+		t.Errorf("Unexpected lengths %d %d", len1, len2)
+	}
+	Diff(data)
+	var expected string = "Equal(0, 0) = false\nEqual(1, 0) = true\n"
+	if log.String() != expected {
+		t.Errorf("Unexpected trace\nGot\n%s\nExpected\n%s", log.String(), expected)
+	}
+}
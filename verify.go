@@ -0,0 +1,92 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"fmt"
+)
+
+// A Stringer returns a readable representation of the element at index i
+// in the first (side 0) or the second (side 1) sequence
+type Stringer func(i, side int) string
+
+// Formats an element index, including its content when a stringer is available
+func (s Stringer) format(i, side int) string {
+	if s == nil {
+		return fmt.Sprint(i)
+	}
+	return fmt.Sprintf("%d (%s)", i, s(i, side))
+}
+
+// A MarkError is returned when a mark is out of bounds or out of order
+type MarkError struct {
+	Side int
+	Mark Mark
+}
+
+func (e *MarkError) Error() string {
+	return fmt.Sprintf("diff: invalid mark %v on side %d", e.Mark, e.Side)
+}
+
+// A MismatchError is returned when a delta keeps two elements that are not equal
+type MismatchError struct {
+	X, Y     int
+	Stringer Stringer
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("diff: mismatch at %s and %s", e.Stringer.format(e.X, 0), e.Stringer.format(e.Y, 1))
+}
+
+// A LengthError is returned when a delta does not keep
+// the same number of elements from both sequences
+type LengthError struct {
+	X, Y int
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("diff: delta keeps %d elements of the first sequence and %d of the second", e.X, e.Y)
+}
+
+// Verifies that the delta transforms the first sequence into the second one,
+// i.e. that its marks are ordered and within bounds and that every pair
+// of elements it leaves unchanged is equal. The optional stringer
+// is used to include the offending elements in a returned MismatchError.
+func Verify(data Interface, d Delta, str Stringer) error {
+	var len1, len2 = data.Len()
+	if err := checkMarks(d.Removed, len1, 0); err != nil {
+		return err
+	}
+	if err := checkMarks(d.Added, len2, 1); err != nil {
+		return err
+	}
+
+	var x, y int
+	for _, c := range d.changes() {
+		for ; x < c.x; x, y = x+1, y+1 {
+			if !data.Equal(x, y) {
+				return &MismatchError{x, y, str}
+			}
+		}
+		x, y = c.lenX, c.lenY
+	}
+	if len1-x != len2-y {
+		return &LengthError{len1 - count(d.Removed), len2 - count(d.Added)}
+	}
+	for ; x < len1; x, y = x+1, y+1 {
+		if !data.Equal(x, y) {
+			return &MismatchError{x, y, str}
+		}
+	}
+	return nil
+}
+
+// Checks that the marks are non-empty, ordered, non-overlapping and within length
+func checkMarks(marks []Mark, length int, side int) error {
+	var end int
+	for _, m := range marks {
+		if m.From < end || m.Length <= m.From || m.Length > length {
+			return &MarkError{side, m}
+		}
+		end = m.Length
+	}
+	return nil
+}
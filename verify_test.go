@@ -0,0 +1,45 @@
+package diff // import "github.com/spaskalev/diff"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	var seq1, seq2 string = "abcdefgh", "abbcedfh"
+	var data Interface = WithEqual(len(seq1), len(seq2), func(i, j int) bool {
+		return seq1[i] == seq2[j]
+	})
+	var str Stringer = func(i, side int) string {
+		if side == 0 {
+			return string(seq1[i])
+		}
+		return string(seq2[i])
+	}
+
+	if err := Verify(data, Diff(data), str); err != nil {
+		t.Errorf("Unexpected error for a computed delta: %v", err)
+	}
+
+	var tampered Delta = Delta{
+		Added:   []Mark{Mark{2, 3}, Mark{6, 7}},
+		Removed: []Mark{Mark{3, 4}, Mark{6, 7}},
+	}
+	err := Verify(data, tampered, str)
+	if _, ok := err.(*MismatchError); !ok {
+		t.Fatalf("Expected a mismatch error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "5 (f)") || !strings.Contains(err.Error(), "5 (d)") {
+		t.Errorf("Expected the error to include the elements, got %v", err)
+	}
+	if err = Verify(data, tampered, nil); err.Error() != "diff: mismatch at 5 and 5" {
+		t.Errorf("Expected an index-only error, got %v", err)
+	}
+
+	if err = Verify(data, Delta{Added: []Mark{Mark{2, 3}}}, nil); err == nil {
+		t.Errorf("Expected an error for an unbalanced delta")
+	}
+	if err = Verify(data, Delta{Added: []Mark{Mark{5, 6}, Mark{2, 3}}}, nil); err == nil {
+		t.Errorf("Expected an error for unordered marks")
+	}
+}